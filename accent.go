@@ -0,0 +1,346 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AccentProfile rewrites pronunciations to those of a regional accent, such
+// as by merging the FOOT and STRUT vowels. Its rules are context-sensitive
+// phoneme rewrite rules, one per line, in this format:
+//
+//	FROM -> TO
+//	FROM -> TO / LEFT _ RIGHT
+//
+// FROM, TO, LEFT and RIGHT are space-separated sequences of phonemes, such as
+// "ɑː" or "t ʃ". FROM is not empty but TO, LEFT and RIGHT can be. In FROM,
+// LEFT and RIGHT (but not in TO):
+//   - "V" is any vowel and "C" is any consonant.
+//   - "#" is a word boundary. It can only be in LEFT or RIGHT.
+//   - Alternatives are separated by "|", as in "f|θ|s".
+//   - A prefix of "ˈ" or "ˌ" matches only a phoneme with primary or
+//     secondary stress, "+" matches either and "-" matches an unstressed
+//     phoneme. With no prefix, stress doesn't matter.
+//
+// Blank lines and lines starting with '#' are ignored. Each rule applies, in
+// order, to the whole pronunciation, from left to right. The stress of the
+// first phoneme matched by FROM moves to the first vowel of TO (or, if there
+// are none, to TO's first phoneme). For example:
+//
+//	# The FOOT-STRUT merger.
+//	ɐ -> ʊ
+//	# Drop non-prevocalic /r/.
+//	ɹ -> / _ C|#
+type AccentProfile struct {
+	Name  string
+	rules []rewriteRule
+}
+
+type rewriteRule struct {
+	from, left, right []phonemePattern
+	to                []string
+}
+
+// phonemePattern matches a phoneme (or a word boundary).
+type phonemePattern struct {
+	alternatives []string
+	stress       rune
+}
+
+// bundledAccentProfiles are the AccentProfiles' sources, keyed by name. They
+// are written for Britfone's (Received Pronunciation) phonemes, except for
+// "non-rhotic", which is for CMUdict's (General American) phonemes.
+var bundledAccentProfiles = map[string]string{
+	"northern": `# Northern English.
+# The FOOT-STRUT merger: no separate STRUT vowel.
+ɐ -> ʊ
+# No trap-bath split: BATH words have the TRAP vowel.
+ɑː -> æ / _ f|θ|s
+ɑː -> æ / _ n t|s|tʃ
+ɑː -> æ / _ m p
+# A lax happy vowel.
+-i -> ɪ / _ #
+`,
+	"american": `# An approximation of General American. Only the systematic differences
+# are rewritten. RP's lost /r/ is restored after the NURSE, NEAR, SQUARE
+# and CURE vowels and, as it usually follows it (as in "car" but not
+# "calm"), after the START vowel. It can't be told apart from no /r/ at all
+# after /ɔː/ (as in "for" and "law") or /ə/.
+ɜː -> ɜː ɹ / _ C|#
++ɪə -> ɪ ɹ / _ C|#
++ɛə -> ɛ ɹ / _ C|#
++ʊə -> ʊ ɹ / _ C|#
+# No trap-bath split.
+ɑː -> æ / _ f|θ|s
+ɑː -> æ / _ n t|s|tʃ|d
+ɑː -> æ / _ m p
+ɑː -> ɑː ɹ / _ C|#
+ɹ -> / ɹ _
+# The LOT vowel is unrounded.
+ɒ -> ɑː
+`,
+	"non-rhotic": `# Drop /r/ that isn't followed by a vowel, for CMUdict's rhotic
+# pronunciations.
+ɜː ɹ -> ɜː / _ C|#
+ɪ ɹ -> ɪə / _ C|#
+ɛ ɹ -> ɛə / _ C|#
+ʊ ɹ -> ʊə / _ C|#
+ɹ -> / V _ C|#
+`,
+}
+
+// BundledAccentProfileNames returns the names of the bundled AccentProfiles,
+// in sorted order.
+func BundledAccentProfileNames() []string {
+	names := make([]string, 0, len(bundledAccentProfiles))
+	for name := range bundledAccentProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BundledAccentProfile returns the named bundled AccentProfile, such as
+// "northern".
+func BundledAccentProfile(name string) (*AccentProfile, error) {
+	src, ok := bundledAccentProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("miileeniol: no bundled accent profile %q", name)
+	}
+	return ParseAccentProfile(name, strings.NewReader(src))
+}
+
+// LoadAccentProfileFile is like ParseAccentProfile but reads from the named
+// file, and the profile's name is the filename.
+func LoadAccentProfileFile(filename string) (*AccentProfile, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := ParseAccentProfile(filename, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return p, nil
+}
+
+// ParseAccentProfile parses an AccentProfile's rules, in the format
+// described by the AccentProfile type.
+func ParseAccentProfile(name string, r io.Reader) (*AccentProfile, error) {
+	p := &AccentProfile{Name: name}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if (text == "") || (text[0] == '#') {
+			continue
+		}
+		rule, err := parseRewriteRule(text)
+		if err != nil {
+			return nil, fmt.Errorf("miileeniol: line %d: %v: %q", line, err, text)
+		}
+		p.rules = append(p.rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func parseRewriteRule(text string) (rule rewriteRule, err error) {
+	i := strings.Index(text, "->")
+	if i < 0 {
+		return rewriteRule{}, fmt.Errorf(`missing "->"`)
+	}
+	from, to, context := text[:i], text[i+2:], ""
+	if j := strings.IndexByte(to, '/'); j >= 0 {
+		to, context = to[:j], to[j+1:]
+	}
+
+	if rule.from, err = parsePatterns(from, false); err != nil {
+		return rewriteRule{}, err
+	} else if len(rule.from) == 0 {
+		return rewriteRule{}, fmt.Errorf("empty FROM")
+	}
+	rule.to = strings.Fields(to)
+	for _, p := range rule.to {
+		if strings.ContainsAny(p, "|#") || (p == "V") || (p == "C") {
+			return rewriteRule{}, fmt.Errorf("TO has a pattern %q", p)
+		}
+	}
+
+	if context != "" {
+		j := strings.IndexByte(context, '_')
+		if (j < 0) || (strings.IndexByte(context[j+1:], '_') >= 0) {
+			return rewriteRule{}, fmt.Errorf(`context needs exactly one "_"`)
+		}
+		if rule.left, err = parsePatterns(context[:j], true); err != nil {
+			return rewriteRule{}, err
+		}
+		if rule.right, err = parsePatterns(context[j+1:], true); err != nil {
+			return rewriteRule{}, err
+		}
+	}
+	return rule, nil
+}
+
+func parsePatterns(s string, allowBoundary bool) ([]phonemePattern, error) {
+	patterns := []phonemePattern(nil)
+	for _, field := range strings.Fields(s) {
+		p := phonemePattern{}
+		for _, prefix := range [...]string{"ˈ", "ˌ", "+", "-"} {
+			if strings.HasPrefix(field, prefix) {
+				p.stress, field = []rune(prefix)[0], field[len(prefix):]
+				break
+			}
+		}
+		for _, alt := range strings.Split(field, "|") {
+			if alt == "" {
+				return nil, fmt.Errorf("empty phoneme in %q", field)
+			} else if (alt == "#") && (!allowBoundary || (p.stress != 0)) {
+				return nil, fmt.Errorf(`misplaced "#"`)
+			}
+			p.alternatives = append(p.alternatives, alt)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// Rewrite applies p's rules to a pronunciation in Britfone's format, such as
+// "b ˈɑː θ", returning the rewritten pronunciation, such as "b ˈæ θ". A
+// pronunciation of several words, separated by " _ ", is rewritten one word
+// at a time.
+func (p *AccentProfile) Rewrite(pronunciation string) string {
+	if (p == nil) || (len(p.rules) == 0) {
+		return pronunciation
+	}
+	words := strings.Split(pronunciation, " _ ")
+	for i, word := range words {
+		phonemes := strings.Fields(word)
+		for _, rule := range p.rules {
+			phonemes = rule.apply(phonemes)
+		}
+		words[i] = strings.Join(phonemes, " ")
+	}
+	return strings.Join(words, " _ ")
+}
+
+func (r *rewriteRule) apply(phonemes []string) []string {
+	for i := 0; i < len(phonemes); {
+		n := len(r.from)
+		if !r.matches(phonemes, i) {
+			i++
+			continue
+		}
+
+		stress := ""
+		if s := phonemes[i]; strings.HasPrefix(s, "ˈ") || strings.HasPrefix(s, "ˌ") {
+			stress = s[:len("ˈ")]
+		}
+		to := append([]string(nil), r.to...)
+		if (stress != "") && (len(to) > 0) {
+			k := 0
+			for j, t := range to {
+				if isVowelPhoneme(t) {
+					k = j
+					break
+				}
+			}
+			to[k] = stress + to[k]
+		}
+
+		tail := append(to, phonemes[i+n:]...)
+		phonemes = append(phonemes[:i], tail...)
+		i += len(to)
+	}
+	return phonemes
+}
+
+// matches returns whether r (including its context) matches phonemes at i.
+func (r *rewriteRule) matches(phonemes []string, i int) bool {
+	if i+len(r.from) > len(phonemes) {
+		return false
+	}
+	for j, p := range r.from {
+		if !p.matches(phonemes[i+j]) {
+			return false
+		}
+	}
+
+	// Match the left context backwards from i and the right context forwards
+	// from the end of FROM. A "#" matches only past either end.
+	j := i - 1
+	for k := len(r.left) - 1; k >= 0; k-- {
+		if p := r.left[k]; (j < 0) && p.isBoundary() {
+			continue
+		} else if (j < 0) || !p.matches(phonemes[j]) {
+			return false
+		}
+		j--
+	}
+	j = i + len(r.from)
+	for _, p := range r.right {
+		if (j >= len(phonemes)) && p.isBoundary() {
+			continue
+		} else if (j >= len(phonemes)) || !p.matches(phonemes[j]) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+func (p *phonemePattern) isBoundary() bool {
+	for _, alt := range p.alternatives {
+		if alt == "#" {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns whether p matches a phoneme, such as "ˈɑː".
+func (p *phonemePattern) matches(phoneme string) bool {
+	base, stress := phoneme, rune(0)
+	if strings.HasPrefix(base, "ˈ") || strings.HasPrefix(base, "ˌ") {
+		stress, base = []rune(base)[0], base[len("ˈ"):]
+	}
+	switch p.stress {
+	case '+':
+		if stress == 0 {
+			return false
+		}
+	case '-':
+		if stress != 0 {
+			return false
+		}
+	case 'ˈ', 'ˌ':
+		if stress != p.stress {
+			return false
+		}
+	}
+	for _, alt := range p.alternatives {
+		switch alt {
+		case base:
+			return true
+		case "V":
+			if isVowelPhoneme(base) {
+				return true
+			}
+		case "C":
+			if !isVowelPhoneme(base) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,363 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Alphabet maps Phoneme symbols, such as "k" or "aɪ", and punctuation marks,
+// such as ",", to how they are written.
+//
+// An Alphabet is defined by a file with one phoneme per line, in this format:
+//
+//	PHONEME LETTER DIACRITIC ROMAN
+//
+// PHONEME is an IPA symbol, such as "k" or "aɪ". LETTER is the gomono text
+// that it is drawn as, such as "K" or "aı". DIACRITIC is what is drawn over
+// that text: "dot", "overline" or "none". ROMAN is its romanization, made of
+// lower-case ASCII letters. For example:
+//
+//	# The PRICE diphthong.
+//	aɪ aı overline ai
+//	k  K  none     k
+//
+// A PHONEME with a length mark, such as "iː", is for the long phoneme only,
+// and one without, such as "i", is for the short phoneme only, but if there
+// is no line for one of them then it is written the same as the other. No two
+// lines can have the same ROMAN, so that romanized text can be read back as
+// phonemes. Blank lines and lines starting with '#' are ignored. Punctuation
+// marks are always written as themselves.
+//
+// A line with two fields, NAME VALUE, is a setting:
+//   - "overlines joined" sets JoinOverlines. The default is "overlines
+//     separate".
+//   - "coda-drop N" sets CodaDrop to N.
+type Alphabet struct {
+	Name string
+
+	// JoinOverlines is whether consecutive overlined letters, within a
+	// syllable, are drawn with one overline spanning them all, instead of an
+	// overline each.
+	JoinOverlines bool
+
+	// CodaDrop is how many pixels lower than the rest of a syllable the
+	// letters after its vowel are drawn.
+	CodaDrop int
+
+	// Letters maps symbols to gomono text. A trailing '\'' or '~' means that
+	// the text is drawn with a dot above or an overline. A symbol with a
+	// length mark, such as "iː", is for long phonemes.
+	Letters map[string]string
+
+	// Roman maps symbols to an ASCII romanization.
+	Roman map[string]string
+}
+
+// alphabetPunctuation are the punctuation marks that every Alphabet writes
+// as themselves.
+const alphabetPunctuation = " '‘’\"“”„‚«»‹›+-–―?!¡¿,.;:()[]…—•·"
+
+// alphabetDiacritics maps a DIACRITIC, in an Alphabet file, to the suffix of
+// a Letters value.
+var alphabetDiacritics = map[string]string{
+	"none":     "",
+	"dot":      "'",
+	"overline": "~",
+}
+
+// bundledAlphabets are the Alphabets' sources, keyed by name.
+var bundledAlphabets = map[string]string{
+	"default": `# The default, 36 letter, alphabet. Vowels are lower case, with a dot
+# above them or an overline, and consonants are upper case. The short (and
+# unstressed) /i/ and /u/ are written like /iː/ and /uː/ but romanized
+# differently.
+aɪ aı overline ai
+aʊ au overline au
+eɪ eı overline ei
+iː ı  dot      ia
+i  ı  dot      iy
+uː u  dot      ue
+u  u  dot      uw
+æ  a  dot      ae
+ɐ  ε  dot      ua
+ɑː a  overline aa
+ɒ  o  dot      oe
+ɔː o  overline oa
+ɔɪ oı overline oi
+ə  ε  overline oo
+əʊ εu overline eu
+ɛ  e  overline ee
+ɛə eε overline eo
+ɜː e  dot      ea
+ɪ  ı  overline ii
+ɪə ıε overline ie
+ʊ  u  overline uu
+ʊə uε overline uo
+
+b  B  none b
+d  D  none d
+dʒ J  none j
+f  F  none f
+g  G  none g
+h  H  none h
+j  Y  none y
+k  K  none k
+l  L  none l
+m  M  none m
+n  N  none n
+p  P  none p
+s  S  none s
+t  T  none t
+tʃ Ч  none tx
+v  V  none v
+w  W  none w
+z  Z  none z
+ð  Δ  none dh
+ŋ  Γ  none ng
+ɹ  R  none r
+ʃ  X  none x
+ʒ  Ж  none zh
+θ  Θ  none th
+`,
+
+	"v0.1": `# The alphabet of old-version-0.1: 32 letters, 10 base consonants and 6
+# base vowels, each of which can be overlined. An overlined consonant is
+# voiced: "B" is an overlined "P". Letters after a syllable's vowel are drawn
+# lower, and an overline spans a run of overlined letters.
+overlines joined
+coda-drop 5
+
+aɪ aı overline ai
+aʊ au overline au
+eɪ eı overline ei
+iː e  none     ea
+i  e  none     ey
+uː u  none     ue
+u  u  none     uw
+æ  a  none     ae
+ɐ  ε  none     ia
+ɑː a  overline aa
+ɒ  o  overline oe
+ɔː o  none     oa
+ɔɪ oı overline oi
+ə  ε  overline oo
+əʊ ou overline ou
+ɛ  e  overline ee
+ɛə eε overline eo
+ɜː ı  none     ua
+ɪ  ı  overline ii
+ɪə ıε overline io
+ʊ  u  overline uu
+ʊə uε overline uo
+
+p  P  none     p
+b  P  overline b
+t  T  none     t
+d  T  overline d
+k  K  none     k
+g  K  overline g
+m  M  none     m
+n  M  overline n
+l  L  none     l
+ɹ  L  overline r
+f  F  none     f
+v  F  overline v
+θ  H  none     c
+ð  H  none     dh
+h  H  overline h
+s  S  none     s
+z  S  overline z
+ʃ  J  none     x
+ʒ  J  overline j
+w  Y  none     w
+j  Y  overline y
+tʃ TJ none     tx
+dʒ TJ overline dj
+ŋ  MK overline ng
+`,
+}
+
+// NewDefaultAlphabet returns a copy of the default (36 letter) alphabet. The
+// copy can be modified without affecting other Alphabets.
+func NewDefaultAlphabet() *Alphabet {
+	a, err := BundledAlphabet("default")
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// BundledAlphabetNames returns the names of the bundled Alphabets, in sorted
+// order.
+func BundledAlphabetNames() []string {
+	names := make([]string, 0, len(bundledAlphabets))
+	for name := range bundledAlphabets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BundledAlphabet returns a copy of the named bundled Alphabet, such as
+// "default".
+func BundledAlphabet(name string) (*Alphabet, error) {
+	src, ok := bundledAlphabets[name]
+	if !ok {
+		return nil, fmt.Errorf("miileeniol: no bundled alphabet %q", name)
+	}
+	return ParseAlphabet(name, strings.NewReader(src))
+}
+
+// LoadAlphabetFile is like ParseAlphabet but reads from the named file, and
+// the alphabet's name is the filename.
+func LoadAlphabetFile(filename string) (*Alphabet, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := ParseAlphabet(filename, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return a, nil
+}
+
+// ParseAlphabet parses an Alphabet, in the format described by the Alphabet
+// type.
+func ParseAlphabet(name string, r io.Reader) (*Alphabet, error) {
+	a := &Alphabet{
+		Name:    name,
+		Letters: map[string]string{},
+		Roman:   map[string]string{},
+	}
+	for _, c := range alphabetPunctuation {
+		a.Letters[string(c)] = string(c)
+	}
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if (text == "") || (text[0] == '#') {
+			continue
+		}
+		if err := a.parseLine(text); err != nil {
+			return nil, fmt.Errorf("miileeniol: line %d: %v: %q", line, err, text)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Alphabet) parseLine(text string) error {
+	fields := strings.Fields(text)
+	if len(fields) == 2 {
+		return a.parseSetting(fields[0], fields[1])
+	} else if len(fields) != 4 {
+		return fmt.Errorf("want 2 or 4 fields, got %d", len(fields))
+	}
+	phoneme, letter, diacritic, roman := fields[0], fields[1], fields[2], fields[3]
+
+	p, err := ParsePhoneme(phoneme)
+	if err != nil {
+		return fmt.Errorf("bad phoneme %q", phoneme)
+	} else if p.Stress != Unstressed {
+		return fmt.Errorf("phoneme %q has a stress mark", phoneme)
+	} else if strings.Contains(alphabetPunctuation, p.Symbol) {
+		return fmt.Errorf("phoneme %q is punctuation", phoneme)
+	} else if _, ok := a.Letters[phoneme]; ok {
+		return fmt.Errorf("duplicate phoneme %q", phoneme)
+	}
+
+	if c := letter[len(letter)-1]; (c == '\'') || (c == '~') {
+		return fmt.Errorf("letter %q ends with %q", letter, c)
+	}
+	suffix, ok := alphabetDiacritics[diacritic]
+	if !ok {
+		return fmt.Errorf("unknown diacritic %q", diacritic)
+	}
+
+	for i := 0; i < len(roman); i++ {
+		if c := roman[i]; (c < 'a') || ('z' < c) {
+			return fmt.Errorf("romanization %q isn't lower-case ASCII", roman)
+		}
+	}
+	for k, v := range a.Roman {
+		if v == roman {
+			return fmt.Errorf("romanization %q is also that of %q", roman, k)
+		}
+	}
+
+	a.Letters[phoneme] = letter + suffix
+	a.Roman[phoneme] = roman
+	return nil
+}
+
+func (a *Alphabet) parseSetting(name string, value string) error {
+	switch name {
+	case "overlines":
+		switch value {
+		case "separate":
+			a.JoinOverlines = false
+			return nil
+		case "joined":
+			a.JoinOverlines = true
+			return nil
+		}
+	case "coda-drop":
+		if n, err := strconv.Atoi(value); (err == nil) && (0 <= n) && (n <= maxCodaDrop) {
+			a.CodaDrop = n
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	return fmt.Errorf("bad %s value %q", name, value)
+}
+
+// maxCodaDrop is the largest CodaDrop that an Alphabet file can set. Lower
+// letters would overlap the next line.
+const maxCodaDrop = 10
+
+// alphabetKey returns the key, in an Alphabet's Letters or Roman map (or a
+// Renderer's glyphs), for p: its symbol, with a length mark if p is long,
+// unless has returns false for that and true for the other length.
+func alphabetKey(p Phoneme, has func(key string) bool) string {
+	k, other := p.Symbol, p.Symbol+lengthMark
+	if p.Long {
+		k, other = other, k
+	}
+	if !has(k) && has(other) {
+		return other
+	}
+	return k
+}
+
+// letter returns how p is written, and whether a has a letter for it.
+func (a *Alphabet) letter(p Phoneme) (string, bool) {
+	s, ok := a.Letters[alphabetKey(p, func(k string) bool {
+		_, ok := a.Letters[k]
+		return ok
+	})]
+	return s, ok
+}
+
+// roman returns p's romanization, and whether a has one for it.
+func (a *Alphabet) roman(p Phoneme) (string, bool) {
+	s, ok := a.Roman[alphabetKey(p, func(k string) bool {
+		_, ok := a.Roman[k]
+		return ok
+	})]
+	return s, ok
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+)
+
+// centeringDiphthongs are what a host word's final long vowel merges into
+// before a "'RE", as in "WE'RE" /wɪə/ or "THEY'RE" /ðɛə/.
+var centeringDiphthongs = map[string]string{
+	"iː": "ɪə",
+	"uː": "ʊə",
+	"eɪ": "ɛə",
+}
+
+// contract returns the pronunciation of an upper-case contraction, such as
+// "HE'LL", "LADY'S" or "SHOULDN'T", that is not in the dictionary, by
+// combining its host word's pronunciation with a form of its clitic. Apart
+// from "N'T", the clitic forms are the dictionary's entries for "'LL",
+// "'S", "'VE", etc.
+//
+// A "'D" after a consonant other than /t/ or /d/ is taken to be a poetic
+// past tense, as in "STAMP'D", rather than "would" or "had".
+func (t *Transliterator) contract(k string, depth int) (string, bool) {
+	i := strings.LastIndexByte(k, '\'')
+	if (i <= 0) || (i == len(k)-1) {
+		return "", false
+	}
+	host, clitic := k[:i], k[i:]
+
+	if (clitic == "'T") && strings.HasSuffix(host, "N") {
+		v, ok := t.lookupBase(host[:len(host)-1], depth)
+		if !ok {
+			return "", false
+		}
+		base := strings.Fields(v)
+		if isVowelPhoneme(lastPhoneme(base)) {
+			return strings.Join(append(base, "n", "t"), " "), true
+		}
+		return strings.Join(append(base, "ə", "n", "t"), " "), true
+	}
+
+	forms := t.cliticForms(clitic)
+	if len(forms) == 0 {
+		return "", false
+	}
+	v, ok := t.lookupBase(host, depth)
+	if !ok {
+		return "", false
+	}
+	base := strings.Fields(v)
+	p := lastPhoneme(base)
+
+	switch clitic {
+	case "'S":
+		if isSibilant(p) {
+			return strings.Join(append(base, "ɪ", "z"), " "), true
+		}
+		for _, form := range forms {
+			if (len(form) == 1) && (isVoiceless(form[0]) == isVoiceless(p)) {
+				return strings.Join(append(base, form...), " "), true
+			}
+		}
+		return "", false
+
+	case "'D":
+		if isVoiceless(p) && (p != "t") {
+			return strings.Join(append(base, "t"), " "), true
+		} else if (p != "") && !isVowelPhoneme(p) && (p != "d") && (p != "t") {
+			return strings.Join(append(base, "d"), " "), true
+		}
+
+	case "'RE":
+		if n := len(base); n > 0 {
+			stress := strings.TrimSuffix(base[n-1], p)
+			if d, ok := centeringDiphthongs[p]; ok {
+				base[n-1] = stress + d
+				return strings.Join(base, " "), true
+			}
+		}
+	}
+
+	// Use a non-syllabic form, such as /l/ for "'LL", after a vowel and a
+	// syllabic form, such as /əl/, after a consonant.
+	if isVowelPhoneme(p) {
+		for _, form := range forms {
+			if !hasVowelPhoneme(form) {
+				return strings.Join(append(base, form...), " "), true
+			}
+		}
+		// Dictionaries list "'M" only as /əm/, so drop the schwa from "I'M".
+		for _, form := range forms {
+			if (len(form) > 1) && (form[0] == "ə") && !hasVowelPhoneme(form[1:]) {
+				return strings.Join(append(base, form[1:]...), " "), true
+			}
+		}
+	} else {
+		for _, form := range forms {
+			if hasVowelPhoneme(form) {
+				return strings.Join(append(base, form...), " "), true
+			}
+		}
+	}
+	return strings.Join(append(base, forms[0]...), " "), true
+}
+
+// cliticForms returns the pronunciations of a clitic, such as "'LL", from
+// its plain and numbered dictionary entries.
+func (t *Transliterator) cliticForms(clitic string) [][]string {
+	forms := [][]string(nil)
+	if v, ok := t.Dictionary.Lookup(clitic); ok {
+		forms = append(forms, strings.Fields(v))
+	}
+	for _, n := range t.Dictionary.Variants(clitic) {
+		if v, ok := t.Dictionary.Variant(clitic, n); ok {
+			forms = append(forms, strings.Fields(v))
+		}
+	}
+	return forms
+}
+
+func hasVowelPhoneme(phonemes []string) bool {
+	for _, p := range phonemes {
+		if isVowelPhoneme(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+// miileeniol-examples generates the miileeniol-example-*.png images. Run it
+// from the repository root.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+const printRoman = false
+
+var texts = [...]string{
+	"" +
+		"Twinkle, twinkle, little star\n" +
+		"How I wonder what you are\n" +
+		"  Up above the world so high\n" +
+		"  Like a diamond in the sky\n" +
+		"When the blazing sun is gone\n" +
+		"When he nothing shines upon\n" +
+		"  Then you show your little light\n" +
+		"  Twinkle, twinkle, all the night\n" +
+		"Then the traveler in the dark\n" +
+		"Thanks you for your tiny spark\n" +
+		"  He could not see which way to go\n" +
+		"  If you did not twinkle so\n" +
+		"In the dark blue sky you keep\n" +
+		"And often through my curtains peep\n" +
+		"  For you never shut your eye\n" +
+		"  Till the sun is in the sky\n" +
+		"As your bright and tiny spark\n" +
+		"Lights the traveler in the dark\n" +
+		"  Though I know not what you are\n" +
+		"  Twinkle, twinkle, little star\n" +
+		"Twinkle, twinkle, little star\n" +
+		"How I wonder what you are\n" +
+		"  Up above the world so high\n" +
+		"  Like a diamond in the sky\n" +
+		"",
+
+	"" +
+		"  Four score and seven years ago our fathers brought forth on this continent, a new nation, conceived in Liberty, and dedicated to the proposition that all men are created equal.\n" +
+		"  Now we are engaged in a great civil war, testing whether that nation, or any nation so conceived and so dedicated, can long endure. We are met on a great battlefield of that war. We have come to dedicate a portion of that field, as a final resting place for those who here gave their lives%a that that nation might live%i. It is altogether fitting and proper that we should do this.\n" +
+		"  But, in a larger sense, we can not dedicate - we can not consecrate - we can not hallow - this ground. The brave men, living and dead, who struggled here, have consecrated it, far above our poor power to add or detract. The world will little note, nor long remember what we say here, but it can never forget what they did here. It is for us the living, rather, to be dedicated here to the unfinished work which they who fought here have thus far so nobly advanced. It is rather for us to be here dedicated to the great task remaining before us - that from these honored dead we take increased devotion to that cause for which they gave the last full measure of devotion - that we here highly resolve that these dead shall not have died in vain - that this nation, under God, shall have a new birth of freedom - and that government of the people, by the people, for the people, shall not perish from the earth.\n" +
+		"",
+
+	"" +
+		"And take me disappearing\n" +
+		"Through the smoke rings of my mind\n" +
+		"Down the foggy ruins of time\n" +
+		"Far past the frozen leaves\n" +
+		"The haunted frightened trees\n" +
+		"Out to the windy%i beach\n" +
+		"Far from the twisted reach of crazy sorrow.\n" +
+		"Yes, to dance beneath the diamond sky\n" +
+		"With one hand waving free\n" +
+		"Silhouetted by the sea\n" +
+		"Circled by the circus sands\n" +
+		"With all memory and fate\n" +
+		"Driven deep beneath the waves\n" +
+		"Let me forget about today until tomorrow.\n" +
+		"\n" +
+		"I must not fear. Fear is the mind killer. Fear is the little death that brings total obliteration. I will face my fear. I will permit it to pass over me and through me. And when it has gone past I will turn the inner eye to see its path. Where the fear has gone there will be nothing. Only I will remain.\n" +
+		"\n" +
+		"Time flies like an arrow. Fruit flies like a banana.\n" +
+		"\n" +
+		"Computers are useless. They can only give you answers.\n" +
+		"",
+
+	"" +
+		"  I have a friend who's an artist and has sometimes taken a view which I don't " +
+		"agree with very well. He'll hold up a flower and say \"look how beautiful it " +
+		"is,\" and I'll agree. Then he says \"I as an artist can see how beautiful this is " +
+		"but you as a scientist take this all apart and it becomes a dull thing,\" and I " +
+		"think that he's kind of nutty. First of all, the beauty that he sees is " +
+		"available to other people and to me too, I believe. Although I may not be quite " +
+		"as refined aesthetically as he is, I can appreciate the beauty of a flower. " +
+		"At the same time, I see much more about the flower than he sees. I could " +
+		"imagine the cells in there, the complicated actions inside, which also have a " +
+		"beauty. I mean it's not just beauty at this dimension, at one centimeter; " +
+		"there's also beauty at smaller dimensions, the inner structure, also the " +
+		"processes. The fact that the colors in the flower evolved in order to attract " +
+		"insects to pollinate it is interesting; it means that insects can see the " +
+		"color. It adds a question: does this aesthetic sense also exist in the lower " +
+		"forms? Why is it aesthetic? All kinds of interesting questions which the " +
+		"science knowledge only adds to the excitement, the mystery and the awe of a " +
+		"flower. It only adds. I don't understand how it subtracts.\n" +
+		"\n" +
+		"It is not from the benevolence of the butcher, the brewer, or the baker that we expect our dinner, but from their regard to their own interest.\n" +
+		"",
+
+	"" +
+		"I returned, and saw under the sun, that the race is not to the swift, nor the battle to the strong, neither yet bread to the wise, nor yet riches to men of understanding, nor yet favor to men of skill; but time and chance happeneth to them all.\n" +
+		"\n" +
+		"Turning and turning in the widening gyre\n" +
+		"The falcon cannot hear the falconer;\n" +
+		"Things fall apart; the center cannot hold;\n" +
+		"Mere anarchy is loosed upon the world,\n" +
+		"The blood dimmed tide is loosed, and everywhere\n" +
+		"The ceremony of innocence is drowned;\n" +
+		"The best lack all conviction, while the worst\n" +
+		"Are full of passionate intensity.\n" +
+		"\n" +
+		"(Internet myth) According to a researcher at Cambridge University, it doesn't matter in what order the letters in a word are, the only important thing is that the first and last letter be at the right place. The rest can be a total mess and you can still read%i it without problem. This is because the human mind does not read%i every letter by itself, but the word as a whole.\n" +
+		"\n" +
+		"Who would know aught of art must learn, act, and then take his ease.\n" +
+		"\n" +
+		"Are those shy Eurasian footwear, cowboy chaps, or jolly earthmoving headgear?\n" +
+		"",
+
+	"" +
+		"Now is the winter of our discontent\n" +
+		"Made glorious summer by this sun of York;\n" +
+		"And all the clouds that lour'd upon our house\n" +
+		"In the deep bosom of the ocean buried.\n" +
+		"Now are our brows bound with victorious wreaths;\n" +
+		"Our bruised arms hung up for monuments;\n" +
+		"Our stern alarums changed to merry meetings,\n" +
+		"Our dreadful marches to delightful measures.\n" +
+		"Grim visaged war hath smooth'd his wrinkled front;\n" +
+		"And now, instead of mounting barbed steeds\n" +
+		"To fright the souls of fearful adversaries,\n" +
+		"He capers nimbly in a lady's chamber\n" +
+		"To the lascivious pleasing of a lute.\n" +
+		"But I, that am not shaped for sportive tricks,\n" +
+		"Nor made to court an amorous looking glass;\n" +
+		"I, that am rudely stamp'd, and want love's majesty\n" +
+		"To strut before a wanton ambling nymph;\n" +
+		"I, that am curtail'd of this fair proportion,\n" +
+		"Cheated of feature by dissembling nature,\n" +
+		"Deformed, unfinish'd, sent before my time\n" +
+		"Into this breathing world, scarce half made up,\n" +
+		"And that so lamely and unfashionable\n" +
+		"That dogs bark at me as I halt by them;\n" +
+		"Why, I, in this weak piping time of peace,\n" +
+		"…\n" +
+		"",
+
+	"" +
+		"Dearest creature in creation\n" +
+		"Studying English pronunciation,\n" +
+		"  I will teach you in my verse\n" +
+		"  Sounds like corpse, corps, horse and worse.\n" +
+		"I will keep you, Susy, busy,\n" +
+		"Make your head with heat grow dizzy;\n" +
+		"  Tear%i in eye, your dress you'll tear%e;\n" +
+		"  Queer, fair seer, hear my prayer.\n" +
+		"Pray, console your loving poet,\n" +
+		"Make my coat look new, dear, sew it!\n" +
+		"  Just compare heart, hear and heard,\n" +
+		"  Dies and diet, lord and word.\n" +
+		"Sword and sward, retain and Britain\n" +
+		"(Mind the latter how it's written).\n" +
+		"  Made has not the sound of bade,\n" +
+		"  Say - said, pay - paid, laid but plaid.\n" +
+		"Now I surely will not plague you\n" +
+		"With such words as vague and ague,\n" +
+		"  But be careful how you speak,\n" +
+		"  Say: gush, bush, steak, streak, break, bleak,\n" +
+		"Previous, precious, fuchsia, via\n" +
+		"Recipe, pipe, studding-sail, choir;\n" +
+		"  Woven, oven, how and low,\n" +
+		"  Script, receipt, shoe, poem, toe.\n" +
+		"…\n" +
+		"",
+
+	"" +
+		"  He sighed and opened the black box and took out his rings and slipped them on. Another box held a set of knives and Klatchian steel, their blades darkened with lamp black. Various cunning and intricate devices were taken from velvet bags and dropped into pockets. A couple of long bladed throwing tlingas were slipped into their sheaths inside his boots. A thin silk line and folding grapnel were wound%a around his waist, over the chainmail shirt. A blowpipe was attached to its leather thong and dropped down the back of his cloak; Teppic picked a slim tin container with an assortment of darts, their tips corked and their stems braille coded for ease of selection in the dark.\n" +
+		"  He winced, checked the blade of his rapier and slung the baldric over his right shoulder, to balance the bag of lead%e slingshot ammunition. As an afterthought he opened his sock drawer and took a pistol crossbow, a flask of oil, a roll of lockpicks and, after some consideration, a punch dagger, a bag of assorted caltrops and a set of brass knuckles.\n" +
+		"  Teppic picked up his hat and checked its lining for the coil of cheesewire. He placed it on his head at a jaunty angle, took a last satisfied look at himself in the mirror, turned on his heel and, very slowly, fell over.\n" +
+		"\n" +
+		"I am so clever that sometimes I don't understand a single word of what I am saying.\n" +
+		"",
+
+	"" +
+		"Once upon a midnight dreary,\n  while I pondered, weak and weary,\n" +
+		"Over many a quaint and curious volume of forgotten lore —\n" +
+		"While I nodded, nearly napping,\n  suddenly there came a tapping,\n" +
+		"As of some one gently rapping, rapping at my chamber door.\n" +
+		"\"'Tis some visitor,\" I muttered, \"tapping at my chamber door —\n" +
+		"                            Only this and nothing more.\"\n" +
+		"Ah, distinctly I remember\n  it was in the bleak December;\n" +
+		"And each separate dying ember wrought its ghost upon the floor.\n" +
+		"Eagerly I wished the morrow; —\n  vainly I had sought to borrow\n" +
+		"From my books surcease of sorrow — sorrow for the lost Lenore —\n" +
+		"For the rare and radiant maiden whom the angels name Lenore —\n" +
+		"                            Nameless here for evermore.\n" +
+		"And the silken, sad, uncertain\n  rustling of each purple curtain\n" +
+		"Thrilled me — filled me with fantastic terrors never felt before;\n" +
+		"So that now, to still the beating\n  of my heart, I stood repeating\n" +
+		"\"'Tis some visitor entreating entrance at my chamber door —\n" +
+		"Some late visitor entreating entrance at my chamber door; —\n" +
+		"                            This it is and nothing more.\"\n" +
+		"…\n" +
+		"",
+}
+
+func do(r *miileeniol.Renderer, outName string, text string) {
+	rgba, err := r.Render(text)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Save that RGBA image to disk.
+	outFile, err := os.Create(outName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer outFile.Close()
+	b := bufio.NewWriter(outFile)
+	err = png.Encode(b, rgba)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = b.Flush()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s\n", outName)
+}
+
+func main() {
+	d, err := miileeniol.NewDefaultDictionary(miileeniol.BritfoneDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	t := miileeniol.NewTransliterator(d, miileeniol.NewDefaultAlphabet())
+	t.Logf = log.Printf
+	r, err := miileeniol.NewRenderer(t)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if printRoman {
+		r.RomanOutput = os.Stdout
+	}
+
+	for i, text := range texts {
+		do(r, fmt.Sprintf("miileeniol-example-%d.png", i), text)
+	}
+}
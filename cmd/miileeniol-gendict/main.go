@@ -0,0 +1,49 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+// miileeniol-gendict regenerates the compiled dictionary that the miileeniol
+// package embeds, from the Britfone files, the package's supplementary words
+// and any override dictionaries named as arguments (which are compiled in as
+// the project layer). Run it from the repository root, or via "go generate",
+// whenever those change:
+//
+//	go run ./cmd/miileeniol-gendict [-britfone dir] [-o file] [override ...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+var (
+	britfone = flag.String("britfone", miileeniol.BritfoneDir,
+		"directory holding the Britfone dictionaries")
+	out = flag.String("o", miileeniol.CompiledDictionaryFilename,
+		"output filename")
+)
+
+func main() {
+	flag.Parse()
+	d, err := miileeniol.NewDefaultDictionary(*britfone)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, filename := range flag.Args() {
+		if err := d.LoadOverridesFile(filename, miileeniol.LayerProject); err != nil {
+			log.Fatal(err)
+		}
+	}
+	b, err := d.MarshalBinary()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, b, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: %d entries, %d bytes\n", *out, d.Len(), len(b))
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runG2P(args []string) int {
+	fs, df := newFlagSet("g2p", "[word ...]")
+	eval := fs.Bool("eval", false, "measure accuracy on held-out dictionary words, instead of guessing")
+	holdOut := fs.Int("holdout", 10, "with -eval, hold out about one in n dictionary words, grouped by stem")
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+	if *eval == (len(args) > 0) {
+		fs.Usage()
+		return exitUsage
+	} else if *holdOut < 2 {
+		logf("invalid -holdout %d", *holdOut)
+		return exitUsage
+	}
+
+	// Use the same dictionary, with its override layers, as the other
+	// commands.
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	d := t.Dictionary
+
+	if *eval {
+		a := miileeniol.EvaluateG2P(d, *holdOut)
+		fmt.Printf("%-24s %6d\n", "held-out words:", a.Words)
+		fmt.Printf("%-24s %6d %5.1f%%\n", "correct:", a.Correct, percent(a.Correct, a.Words))
+		fmt.Printf("%-24s %6d %5.1f%%\n", "correct ignoring stress:", a.CorrectIgnoringStress, percent(a.CorrectIgnoringStress, a.Words))
+		fmt.Printf("%-24s %6d %5.1f%%\n", "phoneme error rate:", a.PhonemeErrors, percent(a.PhonemeErrors, a.Phonemes))
+		return exitOK
+	}
+
+	// Each output line is tab-separated: the word and its guessed
+	// pronunciation. The word is guessed even if it is in the dictionary.
+	g := miileeniol.NewG2P(d)
+	code = exitOK
+	for _, arg := range args {
+		key := strings.ToUpper(arg)
+		guess := g.Guess(key)
+		if guess == "" {
+			logf("can't guess %q", key)
+			code = exitFailure
+			continue
+		}
+		fmt.Printf("%s\t%s\n", key, guess)
+	}
+	return code
+}
+
+func percent(n int, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(d)
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+func runHeteronyms(args []string) int {
+	fs, df := newFlagSet("heteronyms", "[file ...]")
+	out := fs.String("o", "-", `output filename, or "-" for stdout`)
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+
+	text, err := readInput(args)
+	if err != nil {
+		return exitCode(err)
+	}
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	_, decisions := t.Disambiguate(strings.TrimSuffix(text, "\n"))
+
+	w, closer, err := createOutput(*out)
+	if err != nil {
+		return exitCode(err)
+	}
+	b := bufio.NewWriter(w)
+	for _, d := range decisions {
+		if d.Variant == 0 {
+			fmt.Fprintf(b, "%d\t%s\t?\t%s\n", d.Line, d.Word, d.Reason)
+		} else {
+			fmt.Fprintf(b, "%d\t%s%%%d\t%s\t%s\n", d.Line, d.Word, d.Variant, d.Pronunciation, d.Reason)
+		}
+	}
+	if err := b.Flush(); err != nil {
+		closer()
+		return exitCode(err)
+	}
+	return exitCode(closer())
+}
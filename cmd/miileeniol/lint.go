@@ -0,0 +1,85 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runLint(args []string) int {
+	fs, df := newFlagSet("lint", "")
+	out := fs.String("o", "-", `output filename, or "-" for stdout`)
+	format := fs.String("format", "tsv", `output format: "tsv" or "json"`)
+	symbolsFilename := fs.String("symbol-list", "",
+		"Britfone symbols file (default is in the -britfone directory, or embedded in the program)")
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	} else if len(args) > 0 {
+		fs.Usage()
+		return exitUsage
+	} else if (*format != "tsv") && (*format != "json") {
+		logf("unsupported -format %q", *format)
+		return exitUsage
+	}
+
+	if (*symbolsFilename == "") && (*df.britfone != "") {
+		*symbolsFilename = filepath.Join(*df.britfone, miileeniol.BritfoneSymbolsFilename)
+	}
+	symbols := miileeniol.EmbeddedBritfoneSymbols()
+	if *symbolsFilename != "" {
+		s, err := miileeniol.LoadBritfoneSymbolsFile(*symbolsFilename)
+		if err != nil {
+			return exitCode(err)
+		}
+		symbols = s
+	}
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	issues := miileeniol.Lint(t.Dictionary, t.Alphabet, symbols)
+
+	w, closer, err := createOutput(*out)
+	if err != nil {
+		return exitCode(err)
+	}
+	b := bufio.NewWriter(w)
+	if *format == "json" {
+		if issues == nil {
+			issues = []miileeniol.LintIssue{}
+		}
+		e := json.NewEncoder(b)
+		e.SetIndent("", "\t")
+		e.Encode(struct {
+			Entries int                    `json:"entries"`
+			Issues  []miileeniol.LintIssue `json:"issues"`
+		}{t.Dictionary.Len(), issues})
+	} else {
+		// Each output line is tab-separated: the key, its layer, the check
+		// that failed, the severity and a message.
+		for _, i := range issues {
+			fmt.Fprintf(b, "%s\t%s\t%s\t%s\t%s\n", i.Key, i.Layer, i.Check, i.Severity, i.Message)
+		}
+	}
+	if err := b.Flush(); err != nil {
+		closer()
+		return exitCode(err)
+	}
+	if err := closer(); err != nil {
+		return exitCode(err)
+	}
+	for _, i := range issues {
+		if i.Severity == miileeniol.LintError {
+			return exitFailure
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runLookup(args []string) int {
+	fs, df := newFlagSet("lookup", "word ...")
+	layer := fs.Bool("layer", false,
+		`also print which dictionary layer, such as "britfone" or "user", supplied each word`)
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+
+	// Each output line is tab-separated: the word, its pronunciation and its
+	// romanization, and optionally its layer. An unmarked word with numbered
+	// variants is listed once per variant, as "WORD%N".
+	code = exitOK
+	b := bufio.NewWriter(os.Stdout)
+	for _, arg := range args {
+		keys := []string{strings.ToUpper(arg)}
+		if _, ok := t.Dictionary.Lookup(keys[0]); !ok {
+			for _, n := range t.Dictionary.Variants(keys[0]) {
+				keys = append(keys, keys[0]+"%"+strconv.Itoa(n))
+			}
+			if len(keys) > 1 {
+				keys = keys[1:]
+			}
+		}
+
+		for _, key := range keys {
+			w, err := t.TransliterateWord(key)
+			if errors.Is(err, miileeniol.ErrNotInDictionary) {
+				logf("%q not in dictionary", key)
+				code = exitIncomplete
+				continue
+			} else if err != nil {
+				b.Flush()
+				return exitCode(err)
+			}
+			l := wordLayer(t.Dictionary, key, w)
+			if w.Guessed {
+				key = guessedPrefix + key
+			}
+			if *layer {
+				fmt.Fprintf(b, "%s\t%s\t%s\t%s\n", key, w.Pronunciation, t.Romanize(w), l)
+			} else {
+				fmt.Fprintf(b, "%s\t%s\t%s\n", key, w.Pronunciation, t.Romanize(w))
+			}
+		}
+	}
+	if err := b.Flush(); err != nil {
+		return exitCode(err)
+	}
+	return code
+}
+
+// wordLayer returns the dictionary layer that supplied the key's
+// pronunciation, or "derived" or "guessed" if no layer did.
+func wordLayer(d *miileeniol.Dictionary, key string, w miileeniol.Word) string {
+	if i := strings.IndexByte(key, '%'); i >= 0 {
+		if n, err := strconv.Atoi(key[i+1:]); err == nil {
+			key = key[:i] + "(" + strconv.Itoa(n) + ")"
+		}
+	}
+	if l, ok := d.Layer(key); ok {
+		return l
+	} else if w.Guessed {
+		return "guessed"
+	}
+	return "derived"
+}
@@ -0,0 +1,408 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+// miileeniol transliterates English text to Miileeniol.
+//
+// Usage:
+//
+//	miileeniol render        [flags] [file ...]
+//	miileeniol transliterate [flags] [file ...]
+//	miileeniol lookup        [flags] word ...
+//	miileeniol g2p           [flags] word ...
+//	miileeniol g2p -eval     [flags]
+//	miileeniol lint          [flags]
+//	miileeniol heteronyms    [flags] [file ...]
+//	miileeniol reverse       [flags] [file ...]
+//
+// A word in the input text can pick one of its numbered dictionary variants
+// with a "%N" suffix: "Raleigh%2" is pronounced as Britfone's "RALEIGH(2)".
+// Upper-case words missing from the dictionary, such as "BBC", are spelled
+// out letter by letter, unless marked with a "%W" suffix, as in "NHS%W".
+//
+// Unmarked heteronyms, such as "lead" (the verb or the metal), have their
+// pronunciation picked from the neighboring words (unless the
+// -heteronyms=false flag is given). The heteronyms command lists every such
+// decision as tab-separated "line, word, pronunciation, reason" lines, where
+// the word is written as "LEAD%2". A heteronym whose context doesn't pick a
+// pronunciation, as in "the lead singer" or "Lead is heavy", is left
+// unmarked, to be read as its default variant, and listed with a "?"
+// pronunciation. Explicit markers, as in "lead%e" or "lead%2", always win.
+//
+// Inflected forms and contractions missing from the dictionary, such as
+// "dimmed" or "he'll", are derived from their base words (unless the
+// -derive=false flag is given). Other missing words have their pronunciation
+// guessed (unless the -guess=false flag is given). Guessed words are drawn in
+// a different color by render and are prefixed with a "*" by transliterate
+// and lookup.
+//
+// The -accent flag picks the accent: Received Pronunciation, from Britfone,
+// or General American, from a CMUdict file (given by the -cmudict flag) that
+// has to be supplied locally. CMUdict's ARPAbet is converted to the IPA
+// phonemes that Britfone uses. The -accent-profile flag further rewrites
+// every pronunciation with context-sensitive rules, in the format described
+// by the miileeniol.AccentProfile type, from either a file or one of the
+// bundled profiles: "northern" and "american", for Britfone's pronunciations,
+// and "non-rhotic", for CMUdict's. The "american" profile restores /r/ after
+// vowels like those of "car" and "nurse", but not after those of "for" or
+// "letter", where Britfone's phonemes don't show whether there was one.
+//
+// Each word's pronunciation is split into syllables. Render marks letters
+// with primary stress with a dot below them, which the -primary-stress and
+// -secondary-stress flags change to "none", "dot", "ring" or "bar", and the
+// -syllables flag draws a raised dot between syllables. For transliterate,
+// the -syllables flag separates syllables with a ".".
+//
+// Render draws a PNG image unless the -format flag, or the -o filename's
+// extension, picks another format. "svg" has the same layout as the PNG, with
+// the letters, diacritics and stress marks as vector paths and the English
+// as text. "pdf" is a document of A4 (or, with -page-size, Letter) pages,
+// with -margin points of margin, that continues the layout over as many pages
+// as the text needs, with the letters and English set in embedded fonts
+// (gomono and goregular, or the TrueType files given by the -miileeniol-font
+// and -english-font flags). "txt" is Unicode plain text whose diacritics and
+// stress marks are combining characters, with an overline spanning a
+// diphthong. The -text-form flag picks "nfc" or "nfd" normalization, or
+// "ascii", which writes each letter's base text followed by "'" (a dot
+// above) or "~" (an overline), stress as a preceding "^", "`" or "_", and an
+// apostrophe as "\'".
+//
+// Transliterate writes the ASCII romanization of each letter, as given by
+// the alphabet, and keeps the text's lines and punctuation. The -stress flag
+// marks stressed vowels with a following "1" or "2" (primary or secondary
+// stress), or in upper case (primary stress only). An apostrophe separates
+// letters that would otherwise be read as one, as in "niat'xeel"
+// ("nutshell"), so that the romanization can be read back unambiguously.
+//
+// Reverse reads romanized text, as written by transliterate (with the same
+// -alphabet and -stress flags), back as English. Each word becomes the
+// dictionary word with the same phonemes, preferring those whose stress
+// matches and whose main pronunciation it is. The -homophones flag lists
+// every such word instead, as in "[their|there]". Words that match no
+// dictionary word are kept as they are.
+//
+// The -alphabet flag picks how phonemes are written: a bundled alphabet,
+// "default" or "v0.1" (the 32 letter alphabet of the old-version-0.1
+// directory), or an alphabet definition file, in the format described by the
+// miileeniol.Alphabet type. Editing such a file changes the letters (and
+// their romanization) without rebuilding the program.
+//
+// Pronunciations can be added or replaced by override dictionaries, in the
+// format described by the Dictionary.LoadOverrides function: a project
+// dictionary (by default, "miileeniol.dict" in the current directory), then a
+// user dictionary (by default, "miileeniol/user.dict" in the user's
+// configuration directory) and then a document dictionary (given by the
+// -dict flag). Each replaces entries in those before it, and in Britfone. The
+// "lookup -layer" command shows which of these supplied each word.
+//
+// The Britfone dictionaries (and this package's supplementary words) are
+// compiled into the program, so it can be run from any directory. The
+// -britfone flag instead loads them from a directory, such as a checkout's
+// "third-party/Britfone".
+//
+// Input is read from the named files, concatenated, or from stdin if there
+// are none (or if a file is named "-"). Run "miileeniol command -h" for each
+// command's flags.
+//
+// The exit code is 0 on success, 1 on error, 2 on bad usage and 3 if some
+// words were missing from the dictionary. For render and transliterate, the
+// output is still written when words are missing, but it omits them. For
+// lint, the exit code is 1 if any entry has an error, rather than a warning
+// about Britfone's own stress marks. Issues are written as tab-separated
+// "key, layer, check, severity, message" lines or, with -format=json, as a
+// JSON object.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+const (
+	exitOK         = 0
+	exitFailure    = 1
+	exitUsage      = 2
+	exitIncomplete = 3
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands = []command{
+	{"render", "draw text as an image", runRender},
+	{"transliterate", "print text as romanized Miileeniol or IPA", runTransliterate},
+	{"lookup", "print dictionary entries for words", runLookup},
+	{"g2p", "guess pronunciations, or measure how well they're guessed", runG2P},
+	{"lint", "check every dictionary entry's symbols, stress and letters", runLint},
+	{"heteronyms", "list how unmarked heteronyms in text are pronounced", runHeteronyms},
+	{"reverse", "read romanized Miileeniol back as English", runReverse},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: miileeniol command [flags] [args]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", c.name, c.summary)
+	}
+}
+
+func main() {
+	os.Exit(main1(os.Args[1:]))
+}
+
+func main1(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return exitUsage
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return exitOK
+	}
+	fmt.Fprintf(os.Stderr, "miileeniol: unknown command %q\n", args[0])
+	usage()
+	return exitUsage
+}
+
+// dictFlags are the flags, common to every command, that configure the
+// Transliterator.
+type dictFlags struct {
+	britfone    *string
+	accent      *string
+	cmudict     *string
+	profile     *string
+	alphabet    *string
+	projectDict *string
+	userDict    *string
+	docDict     *string
+	variant     *string
+	symbols     *string
+	initialisms *bool
+	derive      *bool
+	heteronyms  *bool
+	guess       *bool
+	quiet       *bool
+}
+
+var variantPolicies = map[string]miileeniol.VariantPolicy{
+	"first-stressed": miileeniol.VariantFirstStressed,
+	"first":          miileeniol.VariantFirst,
+	"none":           miileeniol.VariantNone,
+}
+
+var symbolPolicies = map[string]miileeniol.SymbolPolicy{
+	"auto":   miileeniol.SymbolWordsIfUndrawable,
+	"glyphs": miileeniol.SymbolGlyphs,
+	"words":  miileeniol.SymbolWords,
+}
+
+func newFlagSet(name string, argsUsage string) (*flag.FlagSet, *dictFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: miileeniol %s [flags] %s\n\nflags:\n", name, argsUsage)
+		fs.PrintDefaults()
+	}
+	return fs, &dictFlags{
+		britfone: fs.String("britfone", "",
+			"directory holding the Britfone dictionaries (default is the compiled copy embedded in the program)"),
+		accent: fs.String("accent", miileeniol.AccentRP,
+			`accent: "rp" (Received Pronunciation, from Britfone) or "ga" (General American, from CMUdict)`),
+		cmudict: fs.String("cmudict", miileeniol.CMUdictPath,
+			`CMUdict file, for -accent=ga`),
+		profile: fs.String("accent-profile", "",
+			`accent profile: a rewrite rules file or a bundled profile (`+strings.Join(miileeniol.BundledAccentProfileNames(), ", ")+
+				`); "american" leaves out the /r/ of words like "for" and "letter"`),
+		alphabet: fs.String("alphabet", "default",
+			`alphabet: an alphabet definition file or a bundled alphabet (`+strings.Join(miileeniol.BundledAlphabetNames(), ", ")+`)`),
+		projectDict: fs.String("project-dict", defaultProjectDict,
+			"project override dictionary, ignored if missing at its default path"),
+		userDict: fs.String("user-dict", defaultUserDict(),
+			"user override dictionary, ignored if missing at its default path"),
+		docDict: fs.String("dict", "",
+			"document override dictionary"),
+		variant: fs.String("variant", "first-stressed",
+			`how to pick between an unmarked word's numbered variants: "first-stressed", "first" or "none"`),
+		symbols: fs.String("symbols", "auto",
+			`how to write stand-alone symbols like "&" or "(": "auto" (spell out those with no letter), "glyphs" or "words"`),
+		initialisms: fs.Bool("initialisms", true,
+			`spell out upper-case words missing from the dictionary, such as "BBC", letter by letter`),
+		derive: fs.Bool("derive", true,
+			`derive inflected forms missing from the dictionary, such as "dimmed", from their base words`),
+		heteronyms: fs.Bool("heteronyms", true,
+			`pick the pronunciation of unmarked heteronyms, such as "lead", from their context`),
+		guess: fs.Bool("guess", true,
+			"guess the pronunciation of words missing from the dictionary"),
+		quiet: fs.Bool("q", false, "don't log warnings, such as ambiguous words"),
+	}
+}
+
+// parseFlags parses args, returning the non-flag arguments. A non-negative
+// exit code means that the command should stop.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, int) {
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return nil, exitOK
+	} else if err != nil {
+		return nil, exitUsage
+	}
+	return fs.Args(), -1
+}
+
+func (f *dictFlags) newTransliterator() (*miileeniol.Transliterator, error) {
+	policy, ok := variantPolicies[*f.variant]
+	if !ok {
+		return nil, fmt.Errorf("unsupported -variant %q", *f.variant)
+	}
+	symbolPolicy, ok := symbolPolicies[*f.symbols]
+	if !ok {
+		return nil, fmt.Errorf("unsupported -symbols %q", *f.symbols)
+	}
+	d, err := miileeniol.NewAccentDictionary(*f.accent, *f.britfone, *f.cmudict)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range [...]struct {
+		filename, defaultFilename, layer string
+	}{
+		{*f.projectDict, defaultProjectDict, miileeniol.LayerProject},
+		{*f.userDict, defaultUserDict(), miileeniol.LayerUser},
+		{*f.docDict, "", miileeniol.LayerDocument},
+	} {
+		if o.filename == "" {
+			continue
+		} else if err := d.LoadOverridesFile(o.filename, o.layer); err != nil {
+			if (o.filename == o.defaultFilename) && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+	}
+	a, err := loadAlphabet(*f.alphabet)
+	if err != nil {
+		return nil, err
+	}
+	t := miileeniol.NewTransliterator(d, a)
+	t.VariantPolicy = policy
+	t.SymbolPolicy = symbolPolicy
+	t.Initialisms = *f.initialisms
+	t.Derive = *f.derive
+	t.Heteronyms = *f.heteronyms
+	if *f.profile != "" {
+		if t.AccentProfile, err = loadAccentProfile(*f.profile); err != nil {
+			return nil, err
+		}
+	}
+	if *f.guess {
+		t.G2P = miileeniol.NewG2P(d)
+	}
+	if !*f.quiet {
+		t.Logf = logf
+	}
+	return t, nil
+}
+
+// loadAccentProfile returns the named bundled AccentProfile or, if there is
+// no such profile, loads one from the named file.
+func loadAccentProfile(name string) (*miileeniol.AccentProfile, error) {
+	for _, n := range miileeniol.BundledAccentProfileNames() {
+		if n == name {
+			return miileeniol.BundledAccentProfile(name)
+		}
+	}
+	return miileeniol.LoadAccentProfileFile(name)
+}
+
+// loadAlphabet returns the named bundled Alphabet or, if there is no such
+// alphabet, loads one from the named file.
+func loadAlphabet(name string) (*miileeniol.Alphabet, error) {
+	for _, n := range miileeniol.BundledAlphabetNames() {
+		if n == name {
+			return miileeniol.BundledAlphabet(name)
+		}
+	}
+	return miileeniol.LoadAlphabetFile(name)
+}
+
+// defaultProjectDict is the project override dictionary's default path,
+// relative to the current directory.
+const defaultProjectDict = "miileeniol.dict"
+
+// defaultUserDict returns the user override dictionary's default path, or ""
+// if there is no user configuration directory.
+func defaultUserDict() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "miileeniol", "user.dict")
+}
+
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "miileeniol: "+format+"\n", args...)
+}
+
+// readInput returns the concatenated contents of the named files, or of
+// stdin if there are none, with "\r\n" line endings converted to "\n".
+func readInput(filenames []string) (string, error) {
+	if len(filenames) == 0 {
+		filenames = []string{"-"}
+	}
+	buf := []byte(nil)
+	for _, filename := range filenames {
+		b, err := []byte(nil), error(nil)
+		if filename == "-" {
+			b, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			b, err = ioutil.ReadFile(filename)
+		}
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, b...)
+	}
+	return strings.Replace(string(buf), "\r\n", "\n", -1), nil
+}
+
+// createOutput opens the named file for writing, or stdout if the name is
+// "-". The returned close function must be called, and its error checked.
+func createOutput(filename string) (io.Writer, func() error, error) {
+	if filename == "-" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+// exitCode reports err, if non-nil, and returns the matching exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "miileeniol: ") {
+		msg = "miileeniol: " + msg
+	}
+	fmt.Fprintln(os.Stderr, msg)
+	if errors.Is(err, miileeniol.ErrNotInDictionary) {
+		return exitIncomplete
+	}
+	return exitFailure
+}
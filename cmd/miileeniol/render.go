@@ -0,0 +1,158 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runRender(args []string) int {
+	fs, df := newFlagSet("render", "[file ...]")
+	out := fs.String("o", "miileeniol.png", `output filename, or "-" for stdout`)
+	format := fs.String("format", "", `output format: "png", "svg", "pdf" or "txt" (default: from the -o extension)`)
+	textForm := fs.String("text-form", "nfc",
+		`for -format=txt: "nfc" or "nfd" (Unicode, with combining diacritics) or "ascii"`)
+	pageSize := fs.String("page-size", "a4", `for -format=pdf: "a4" or "letter"`)
+	margin := fs.Float64("margin", 36, "for -format=pdf: page margin, in points")
+	miileeniolFont := fs.String("miileeniol-font", "",
+		"for -format=pdf: TrueType font file for the Miileeniol letters (default gomono)")
+	englishFont := fs.String("english-font", "",
+		"for -format=pdf: TrueType font file for the English (default goregular)")
+	width := fs.Int("width", 256*7, "image width, in pixels")
+	height := fs.Int("height", 256*5, "image height, in pixels")
+	primary := fs.String("primary-stress", "dot",
+		`mark below letters with primary stress: "none", "dot", "ring" or "bar"`)
+	secondary := fs.String("secondary-stress", "none",
+		`mark below letters with secondary stress: "none", "dot", "ring" or "bar"`)
+	syllables := fs.Bool("syllables", false, "draw a raised dot between syllables")
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*out)), ".")
+		if *format == "" {
+			*format = "png"
+		}
+	}
+	form, ok := textForms[*textForm]
+	if (*format != "png") && (*format != "svg") && (*format != "pdf") && (*format != "txt") {
+		logf("unsupported -format %q", *format)
+		return exitUsage
+	} else if !ok {
+		logf("unsupported -text-form %q", *textForm)
+		return exitUsage
+	}
+	pdfOpts := miileeniol.DefaultPDFOptions()
+	if pdfOpts.PageSize, ok = pageSizes[*pageSize]; !ok {
+		logf("unsupported -page-size %q", *pageSize)
+		return exitUsage
+	} else if *margin < 0 {
+		logf("invalid -margin %g", *margin)
+		return exitUsage
+	}
+	pdfOpts.Margin = *margin
+	primaryMark, ok := stressMarks[*primary]
+	if !ok {
+		logf("unsupported -primary-stress %q", *primary)
+		return exitUsage
+	}
+	secondaryMark, ok := stressMarks[*secondary]
+	if !ok {
+		logf("unsupported -secondary-stress %q", *secondary)
+		return exitUsage
+	}
+	if (*width <= 0) || (*height <= 0) {
+		logf("invalid image size %dx%d", *width, *height)
+		return exitUsage
+	}
+
+	text, err := readInput(args)
+	if err != nil {
+		return exitCode(err)
+	}
+	if *miileeniolFont != "" {
+		if pdfOpts.MiileeniolFont, err = ioutil.ReadFile(*miileeniolFont); err != nil {
+			return exitCode(err)
+		}
+	}
+	if *englishFont != "" {
+		if pdfOpts.EnglishFont, err = ioutil.ReadFile(*englishFont); err != nil {
+			return exitCode(err)
+		}
+	}
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	r, err := miileeniol.NewRenderer(t)
+	if err != nil {
+		return exitCode(err)
+	}
+	r.Width, r.Height = *width, *height
+	r.PrimaryStressMark, r.SecondaryStressMark = primaryMark, secondaryMark
+	r.SyllableBoundaries = *syllables
+
+	// Render to memory first, so that failures don't leave a truncated file.
+	buf := &bytes.Buffer{}
+	renderErr := error(nil)
+	switch *format {
+	case "svg":
+		renderErr = r.RenderSVG(buf, text)
+	case "pdf":
+		renderErr = r.RenderPDF(buf, text, pdfOpts)
+	case "txt":
+		renderErr = r.RenderText(buf, text, form)
+	default:
+		renderErr = r.RenderPNG(buf, text)
+	}
+	if buf.Len() == 0 {
+		return exitCode(renderErr)
+	}
+
+	w, closer, err := createOutput(*out)
+	if err != nil {
+		return exitCode(err)
+	}
+	if _, err := buf.WriteTo(w); err != nil {
+		closer()
+		return exitCode(err)
+	}
+	if err := closer(); err != nil {
+		return exitCode(err)
+	}
+	if err := renderErr; err != nil {
+		return exitCode(err)
+	}
+	if *out != "-" {
+		fmt.Println(*out)
+	}
+	return exitOK
+}
+
+var stressMarks = map[string]miileeniol.StressMark{
+	"none": miileeniol.StressMarkNone,
+	"dot":  miileeniol.StressMarkDot,
+	"ring": miileeniol.StressMarkRing,
+	"bar":  miileeniol.StressMarkBar,
+}
+
+var pageSizes = map[string]miileeniol.PageSize{
+	"a4":     miileeniol.PageSizeA4,
+	"letter": miileeniol.PageSizeLetter,
+}
+
+var textForms = map[string]miileeniol.TextForm{
+	"nfc":   miileeniol.TextNFC,
+	"nfd":   miileeniol.TextNFD,
+	"ascii": miileeniol.TextASCII,
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runReverse(args []string) int {
+	fs, df := newFlagSet("reverse", "[file ...]")
+	out := fs.String("o", "-", `output filename, or "-" for stdout`)
+	stress := fs.String("stress", "none",
+		`how the input marks stress: "none", "digits" or "upper", as for transliterate`)
+	homophones := fs.Bool("homophones", false,
+		`write every candidate word, best first, as in "[their|there|they're]"`)
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+
+	romanStress, ok := romanStresses[*stress]
+	if !ok {
+		logf("unsupported -stress %q", *stress)
+		return exitUsage
+	}
+
+	text, err := readInput(args)
+	if err != nil {
+		return exitCode(err)
+	}
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	t.RomanStress = romanStress
+	// Words that transliterate marked as guessed are read like any other.
+	text = strings.Replace(text, guessedPrefix, "", -1)
+	reversals, reverseErr := miileeniol.NewReverseIndex(t).ReverseText(text)
+	if reversals == nil {
+		return exitCode(reverseErr)
+	}
+
+	w, closer, err := createOutput(*out)
+	if err != nil {
+		return exitCode(err)
+	}
+	b := bufio.NewWriter(w)
+	for _, r := range reversals {
+		switch {
+		case (r.Phonemes == nil) || (len(r.Candidates) == 0):
+			b.WriteString(r.Text)
+		case *homophones && (len(r.Candidates) > 1):
+			b.WriteByte('[')
+			for i, c := range r.Candidates {
+				if i > 0 {
+					b.WriteByte('|')
+				}
+				b.WriteString(strings.ToLower(c.English))
+			}
+			b.WriteByte(']')
+		default:
+			b.WriteString(strings.ToLower(r.Candidates[0].English))
+		}
+	}
+	if err := b.Flush(); err != nil {
+		closer()
+		return exitCode(err)
+	}
+	if err := closer(); err != nil {
+		return exitCode(err)
+	}
+	return exitCode(reverseErr)
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package main
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/nigeltao/miileeniol"
+)
+
+func runTransliterate(args []string) int {
+	fs, df := newFlagSet("transliterate", "[file ...]")
+	out := fs.String("o", "-", `output filename, or "-" for stdout`)
+	format := fs.String("format", "roman", `output format: "roman" or "ipa"`)
+	stress := fs.String("stress", "none",
+		`how -format=roman marks stress: "none", "digits" (a "1" or "2" after the vowel) or "upper" (primary stressed vowels in upper case)`)
+	syllables := fs.Bool("syllables", false, `separate syllables with a "."`)
+	args, code := parseFlags(fs, args)
+	if code >= 0 {
+		return code
+	}
+
+	wordString := (func(*miileeniol.Transliterator, miileeniol.Word) string)(nil)
+	switch *format {
+	case "roman":
+		wordString = romanString
+	case "ipa":
+		wordString = ipaString
+	default:
+		logf("unsupported -format %q", *format)
+		return exitUsage
+	}
+
+	romanStress, ok := romanStresses[*stress]
+	if !ok {
+		logf("unsupported -stress %q", *stress)
+		return exitUsage
+	}
+	if *syllables {
+		wordString = syllablesString(wordString)
+	}
+
+	text, err := readInput(args)
+	if err != nil {
+		return exitCode(err)
+	}
+	t, err := df.newTransliterator()
+	if err != nil {
+		return exitCode(err)
+	}
+	t.RomanStress = romanStress
+	lines, transliterateErr := t.TransliterateText(strings.TrimSuffix(text, "\n"))
+	if lines == nil {
+		return exitCode(transliterateErr)
+	}
+
+	w, closer, err := createOutput(*out)
+	if err != nil {
+		return exitCode(err)
+	}
+	b := bufio.NewWriter(w)
+	for _, line := range lines {
+		for _, word := range line {
+			b.WriteString(word.Space)
+			b.WriteString(wordString(t, word))
+		}
+		b.WriteByte('\n')
+	}
+	if err := b.Flush(); err != nil {
+		closer()
+		return exitCode(err)
+	}
+	if err := closer(); err != nil {
+		return exitCode(err)
+	}
+	return exitCode(transliterateErr)
+}
+
+var romanStresses = map[string]miileeniol.RomanStress{
+	"none":   miileeniol.RomanStressNone,
+	"digits": miileeniol.RomanStressDigits,
+	"upper":  miileeniol.RomanStressUpper,
+}
+
+// missingString is how a word missing from the dictionary is printed: as
+// the (upper-cased) English, which cannot be confused with the lower-case
+// romanization.
+func missingString(w miileeniol.Word) string {
+	return w.English
+}
+
+// guessedPrefix marks words whose pronunciation was guessed.
+const guessedPrefix = "*"
+
+func romanString(t *miileeniol.Transliterator, w miileeniol.Word) string {
+	if w.Letters == nil {
+		return missingString(w)
+	} else if w.Guessed {
+		return guessedPrefix + t.Romanize(w)
+	}
+	return t.Romanize(w)
+}
+
+func ipaString(t *miileeniol.Transliterator, w miileeniol.Word) string {
+	if w.Letters == nil {
+		return missingString(w)
+	} else if w.Pronunciation == "" {
+		return w.English
+	}
+	ipa := strings.Replace(w.Pronunciation, " ", "", -1)
+	ipa = strings.Replace(ipa, "_", " ", -1)
+	if w.Guessed {
+		ipa = guessedPrefix + ipa
+	}
+	return ipa + w.Suffix
+}
+
+// syllableSeparator separates syllables when transliterating with the
+// -syllables flag.
+const syllableSeparator = "."
+
+// syllablesString returns a wordString function that is like f but
+// separates the syllables of each word.
+func syllablesString(f func(*miileeniol.Transliterator, miileeniol.Word) string) func(*miileeniol.Transliterator, miileeniol.Word) string {
+	return func(t *miileeniol.Transliterator, w miileeniol.Word) string {
+		if (w.Letters == nil) || (len(w.Syllables) < 2) {
+			return f(t, w)
+		}
+		s := ""
+		if w.Guessed {
+			s = guessedPrefix
+		}
+		for i, y := range w.Syllables {
+			if i > 0 {
+				if w.Syllables[i-1].End == y.Start {
+					s += syllableSeparator
+				} else {
+					s += " "
+				}
+			}
+			part := w
+			part.Letters, part.Guessed = w.Letters[y.Start:y.End], false
+			part.Pronunciation = miileeniol.FormatPronunciation(part.Letters)
+			part.Suffix = ""
+			s += f(t, part)
+		}
+		part := w
+		part.Letters, part.Guessed = w.Letters[w.Syllables[len(w.Syllables)-1].End:], false
+		part.Pronunciation, part.Suffix = "", ""
+		for _, l := range part.Letters {
+			part.Suffix += l.Symbol
+		}
+		part.Letters = nil
+		return s + part.Suffix
+	}
+}
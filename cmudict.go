@@ -0,0 +1,224 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CMUdictPath is the default path, relative to the repository root, of a
+// CMUdict file. CMUdict is not bundled with this repository, so it has to be
+// supplied locally.
+const CMUdictPath = "third-party/cmudict/cmudict.dict"
+
+// LayerCMUdict is the layer of a Dictionary's CMUdict entries.
+const LayerCMUdict = "cmudict"
+
+// The accents that a Dictionary can be built for, as per NewAccentDictionary.
+const (
+	// AccentRP is Received Pronunciation, from Britfone.
+	AccentRP = "rp"
+
+	// AccentGA is General American, from CMUdict.
+	AccentGA = "ga"
+)
+
+// arpabetVowels maps ARPAbet vowels to the IPA phonemes (in Britfone's
+// inventory, which the default Alphabet has letters for) of their stressed
+// and unstressed forms. General American's rhotic vowels are written as a
+// vowel followed by /ɹ/ and its GOAT vowel /oʊ/ is written as /əʊ/.
+var arpabetVowels = map[string][2]string{
+	"AA": {"ɑː", "ɑː"},
+	"AE": {"æ", "æ"},
+	"AH": {"ɐ", "ə"},
+	"AO": {"ɔː", "ɔː"},
+	"AW": {"aʊ", "aʊ"},
+	"AY": {"aɪ", "aɪ"},
+	"EH": {"ɛ", "ɛ"},
+	"ER": {"ɜː ɹ", "ə ɹ"},
+	"EY": {"eɪ", "eɪ"},
+	"IH": {"ɪ", "ɪ"},
+	"IY": {"iː", "i"},
+	"OW": {"əʊ", "əʊ"},
+	"OY": {"ɔɪ", "ɔɪ"},
+	"UH": {"ʊ", "ʊ"},
+	"UW": {"uː", "u"},
+}
+
+var arpabetConsonants = map[string]string{
+	"B":  "b",
+	"CH": "tʃ",
+	"D":  "d",
+	"DH": "ð",
+	"F":  "f",
+	"G":  "g",
+	"HH": "h",
+	"JH": "dʒ",
+	"K":  "k",
+	"L":  "l",
+	"M":  "m",
+	"N":  "n",
+	"NG": "ŋ",
+	"P":  "p",
+	"R":  "ɹ",
+	"S":  "s",
+	"SH": "ʃ",
+	"T":  "t",
+	"TH": "θ",
+	"V":  "v",
+	"W":  "w",
+	"Y":  "j",
+	"Z":  "z",
+	"ZH": "ʒ",
+}
+
+// ARPAbetToIPA converts a space-separated ARPAbet pronunciation, such as
+// "W ER1 D", to Britfone's format, such as "w ˈɜː ɹ d". A vowel's stress
+// digit, 1 for primary or 2 for secondary, becomes an "ˈ" or "ˌ" prefix.
+func ARPAbetToIPA(arpabet string) (string, error) {
+	phonemes := []string(nil)
+	for _, p := range strings.Fields(arpabet) {
+		if v, ok := arpabetConsonants[p]; ok {
+			phonemes = append(phonemes, v)
+			continue
+		}
+		n := len(p) - 1
+		if (n < 1) || (p[n] < '0') || ('2' < p[n]) {
+			return "", fmt.Errorf("miileeniol: bad ARPAbet phoneme %q", p)
+		}
+		forms, ok := arpabetVowels[p[:n]]
+		if !ok {
+			return "", fmt.Errorf("miileeniol: bad ARPAbet phoneme %q", p)
+		}
+		switch p[n] {
+		case '0':
+			phonemes = append(phonemes, forms[1])
+		case '1':
+			phonemes = append(phonemes, "ˈ"+forms[0])
+		case '2':
+			phonemes = append(phonemes, "ˌ"+forms[0])
+		}
+	}
+	if len(phonemes) == 0 {
+		return "", fmt.Errorf("miileeniol: empty ARPAbet pronunciation")
+	}
+	return strings.Join(phonemes, " "), nil
+}
+
+// NewAccentDictionary returns a Dictionary for the given accent, such as
+// AccentRP or AccentGA. For AccentRP, it is NewDefaultDictionary(britfoneDir)
+// or, if britfoneDir is empty, NewEmbeddedDictionary(). For AccentGA, the
+// words are from the CMUdict file, converted to IPA, plus the Britfone
+// expansions and those of this package's supplementary words that CMUdict
+// lacks.
+func NewAccentDictionary(accent string, britfoneDir string, cmudictFilename string) (*Dictionary, error) {
+	switch accent {
+	case AccentRP:
+		if britfoneDir == "" {
+			return NewEmbeddedDictionary()
+		}
+		return NewDefaultDictionary(britfoneDir)
+	case AccentGA:
+		d := NewDictionary()
+		if err := d.LoadCMUdictFile(cmudictFilename); err != nil {
+			return nil, err
+		}
+		if britfoneDir == "" {
+			e, err := NewEmbeddedDictionary()
+			if err != nil {
+				return nil, err
+			}
+			d.expansions = e.expansions
+		} else if err := d.LoadBritfoneExpansionsFile(filepath.Join(britfoneDir, BritfoneExpansionsFilename)); err != nil {
+			return nil, err
+		}
+		for k, v := range supplement {
+			if _, ok := d.Lookup(k); ok {
+				continue
+			} else if base, _ := splitVariant(k); len(d.Variants(base)) > 0 {
+				continue
+			}
+			d.set(k, v, LayerSupplement)
+		}
+		return d, nil
+	}
+	return nil, fmt.Errorf("miileeniol: unsupported accent %q", accent)
+}
+
+// LoadCMUdictFile is like LoadCMUdict but reads from the named file.
+func (d *Dictionary) LoadCMUdictFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := d.LoadCMUdict(f); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// LoadCMUdict adds the entries of a CMUdict file, one "WORD  ARPABET" per
+// line, converting their pronunciations with ARPAbetToIPA. Lines starting
+// with ";;;" and trailing "# comments" are ignored, and words are
+// upper-cased.
+//
+// CMUdict lists a word's alternative pronunciations after the plain "WORD",
+// numbered "WORD(1)", "WORD(2)", etc. or, in newer versions, from "WORD(2)".
+// A word with alternatives gets numbered variants, as Britfone's do, from 1
+// and in the order listed: the plain "WORD" becomes "WORD(1)".
+func (d *Dictionary) LoadCMUdict(r io.Reader) error {
+	type entry struct {
+		key, ipa string
+	}
+	entries := []entry(nil)
+	numVariants := map[string]int{}
+
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if (text == "") || strings.HasPrefix(text, ";;;") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return fmt.Errorf("miileeniol: line %d: bad CMUdict entry: %q", line, text)
+		}
+		ipa, err := ARPAbetToIPA(strings.Join(fields[1:], " "))
+		if err != nil {
+			return fmt.Errorf("miileeniol: line %d: bad CMUdict pronunciation: %q", line, text)
+		}
+		k, n := splitVariant(strings.ToUpper(fields[0]))
+		if (n > 0) != (numVariants[k] > 0) {
+			return fmt.Errorf("miileeniol: line %d: CMUdict key %q is duplicate or out of order", line, fields[0])
+		}
+		numVariants[k]++
+		entries = append(entries, entry{k, ipa})
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	seen := map[string]int{}
+	for _, e := range entries {
+		k := e.key
+		if numVariants[k] > 1 {
+			seen[k]++
+			k += "(" + strconv.Itoa(seen[k]) + ")"
+		}
+		d.set(k, e.ipa, LayerCMUdict)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+//go:generate go run ./cmd/miileeniol-gendict
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// CompiledDictionaryFilename is the filename, relative to the repository
+// root, of the compiled default dictionary that NewEmbeddedDictionary uses.
+// The miileeniol-gendict command regenerates it, and must be re-run (e.g. by
+// "go generate") whenever the Britfone files or the supplementary words
+// change.
+const CompiledDictionaryFilename = "dict.bin"
+
+//go:embed dict.bin
+var embeddedDictionary string
+
+//go:embed third-party/Britfone/britfone.symbols.3.0.1.txt
+var embeddedBritfoneSymbols string
+
+// compiledMagic starts a compiled dictionary. The format, after that, is:
+//   - the offsets of the layers section and of the expansions section, and
+//     the number of entries, then each entry's offset, in key order. These
+//     are little-endian uint32s and the offsets are from the start of the
+//     compiled dictionary.
+//   - the entries, each one its key, its pronunciation and a byte indexing
+//     the layer names.
+//   - the layers section: the number of layer names, then the names.
+//   - the expansions section: the number of expansion keys, then each key,
+//     its number of expansions and the expansions, in key order.
+//
+// Other numbers are unsigned varints. Strings are a varint length and then
+// that many bytes.
+//
+// The entries are not decoded when loaded. Instead, they are found by
+// binary search over the offsets.
+const compiledMagic = "miileeniol dict 1\n"
+
+var errBadCompiledDictionary = errors.New("miileeniol: bad compiled dictionary")
+
+// compiledDictionary is a Dictionary's read-only base layer.
+type compiledDictionary struct {
+	data    string
+	offsets string
+	layers  []string
+}
+
+// NewEmbeddedDictionary returns a Dictionary equivalent to
+// NewDefaultDictionary(BritfoneDir), using a compiled copy that is embedded
+// in the program. Unlike NewDefaultDictionary, it does not need the Britfone
+// files and does not parse them: its entries are searched in place.
+func NewEmbeddedDictionary() (*Dictionary, error) {
+	d := NewDictionary()
+	if err := d.decode(embeddedDictionary); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// EmbeddedBritfoneSymbols returns the list of Britfone symbols, as per
+// LoadBritfoneSymbols, from a copy that is embedded in the program.
+func EmbeddedBritfoneSymbols() map[string]bool {
+	symbols, _ := LoadBritfoneSymbols(strings.NewReader(embeddedBritfoneSymbols))
+	return symbols
+}
+
+// MarshalBinary encodes d in the compiled dictionary format, as embedded by
+// NewEmbeddedDictionary. The encoding is deterministic.
+func (d *Dictionary) MarshalBinary() ([]byte, error) {
+	keys := d.Keys()
+	headerLen := len(compiledMagic) + 4*(3+len(keys))
+	b := make([]byte, headerLen)
+	copy(b, compiledMagic)
+	putUint32 := func(i int, x int) {
+		binary.LittleEndian.PutUint32(b[len(compiledMagic)+4*i:], uint32(x))
+	}
+	buf := [binary.MaxVarintLen64]byte{}
+	appendUvarint := func(x int) {
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(x))]...)
+	}
+	appendString := func(s string) {
+		appendUvarint(len(s))
+		b = append(b, s...)
+	}
+
+	layerIndexes := map[string]int{}
+	layers := []string(nil)
+	for _, k := range keys {
+		if layer, _ := d.Layer(k); !containsString(layers, layer) {
+			layers = append(layers, layer)
+		}
+	}
+	sort.Strings(layers)
+	if len(layers) > 0xFF {
+		return nil, errors.New("miileeniol: too many dictionary layers")
+	}
+	for i, layer := range layers {
+		layerIndexes[layer] = i
+	}
+
+	putUint32(2, len(keys))
+	for i, k := range keys {
+		putUint32(3+i, len(b))
+		v, _ := d.Lookup(k)
+		layer, _ := d.Layer(k)
+		appendString(k)
+		appendString(v)
+		b = append(b, byte(layerIndexes[layer]))
+	}
+
+	putUint32(0, len(b))
+	appendUvarint(len(layers))
+	for _, layer := range layers {
+		appendString(layer)
+	}
+
+	putUint32(1, len(b))
+	keys = keys[:0]
+	for k := range d.expansions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	appendUvarint(len(keys))
+	for _, k := range keys {
+		es := d.Expansions(k)
+		appendString(k)
+		appendUvarint(len(es))
+		for _, e := range es {
+			appendString(e)
+		}
+	}
+	if uint64(len(b)) > 0xFFFFFFFF {
+		return nil, errors.New("miileeniol: dictionary is too large to compile")
+	}
+	return b, nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalBinary replaces d's contents with a compiled dictionary, as
+// encoded by MarshalBinary.
+func (d *Dictionary) UnmarshalBinary(data []byte) error {
+	*d = *NewDictionary()
+	return d.decode(string(data))
+}
+
+// decode sets d's base layer to the compiled dictionary s, which is used in
+// place, and d's expansions to those of s. Only the (small) layers and
+// expansions sections are decoded.
+func (d *Dictionary) decode(s string) error {
+	fixedLen := len(compiledMagic) + 4*3
+	if (len(s) < fixedLen) || !strings.HasPrefix(s, compiledMagic) {
+		return errBadCompiledDictionary
+	}
+	uint32At := func(i int) int {
+		return stringUint32(s[len(compiledMagic)+4*i:])
+	}
+	layersOffset, expansionsOffset, n := uint32At(0), uint32At(1), uint32At(2)
+	if (n > (len(s)-fixedLen)/4) || (layersOffset < fixedLen+4*n) ||
+		(layersOffset > expansionsOffset) || (expansionsOffset > len(s)) {
+		return errBadCompiledDictionary
+	}
+	c := &compiledDictionary{
+		data:    s,
+		offsets: s[fixedLen : fixedLen+4*n],
+	}
+	for i := 0; i < n; i++ {
+		if o := c.offset(i); (o < fixedLen+4*n) || (o >= layersOffset) {
+			return errBadCompiledDictionary
+		}
+	}
+
+	r := compiledReader{s: s[layersOffset:expansionsOffset]}
+	numLayers := r.readUvarint()
+	if numLayers > 0xFF {
+		return errBadCompiledDictionary
+	}
+	c.layers = make([]string, numLayers)
+	for i := range c.layers {
+		c.layers[i] = r.readString()
+	}
+	if r.bad || (r.s != "") {
+		return errBadCompiledDictionary
+	}
+
+	r = compiledReader{s: s[expansionsOffset:]}
+	expansions := map[string][]string{}
+	for m := r.readUvarint(); (m > 0) && !r.bad; m-- {
+		k := r.readString()
+		for e := r.readUvarint(); (e > 0) && !r.bad; e-- {
+			expansions[k] = append(expansions[k], r.readString())
+		}
+	}
+	if r.bad || (r.s != "") {
+		return errBadCompiledDictionary
+	}
+
+	d.compiled, d.expansions = c, expansions
+	return nil
+}
+
+// compiledReader reads varints and strings. Bad data sets bad and empties s,
+// so that later reads return zero values.
+type compiledReader struct {
+	s   string
+	bad bool
+}
+
+func (r *compiledReader) readUvarint() int {
+	x, shift := 0, uint(0)
+	for i := 0; (i < len(r.s)) && (shift < 35); i++ {
+		c := r.s[i]
+		x |= int(c&0x7F) << shift
+		if c < 0x80 {
+			r.s = r.s[i+1:]
+			return x
+		}
+		shift += 7
+	}
+	r.s, r.bad = "", true
+	return 0
+}
+
+func (r *compiledReader) readString() string {
+	n := r.readUvarint()
+	if n > len(r.s) {
+		r.s, r.bad = "", true
+		return ""
+	}
+	s := r.s[:n]
+	r.s = r.s[n:]
+	return s
+}
+
+func (c *compiledDictionary) len() int {
+	return len(c.offsets) / 4
+}
+
+func (c *compiledDictionary) offset(i int) int {
+	return stringUint32(c.offsets[4*i:])
+}
+
+// stringUint32 returns the little-endian uint32 at the start of s.
+func stringUint32(s string) int {
+	return int(s[0]) | int(s[1])<<8 | int(s[2])<<16 | int(s[3])<<24
+}
+
+// entry returns the i'th entry. A bad entry has an empty key.
+func (c *compiledDictionary) entry(i int) (k string, v string, layer string) {
+	r := compiledReader{s: c.data[c.offset(i):]}
+	k, v = r.readString(), r.readString()
+	if r.bad || (r.s == "") || (int(r.s[0]) >= len(c.layers)) {
+		return "", "", ""
+	}
+	return k, v, c.layers[r.s[0]]
+}
+
+func (c *compiledDictionary) key(i int) string {
+	r := compiledReader{s: c.data[c.offset(i):]}
+	return r.readString()
+}
+
+// search returns the index of the first key that is >= k.
+func (c *compiledDictionary) search(k string) int {
+	return sort.Search(c.len(), func(i int) bool {
+		return c.key(i) >= k
+	})
+}
+
+func (c *compiledDictionary) lookup(k string) (v string, layer string, ok bool) {
+	if i := c.search(k); i < c.len() {
+		if key, v, layer := c.entry(i); (key == k) && (key != "") {
+			return v, layer, true
+		}
+	}
+	return "", "", false
+}
+
+// keysWithPrefix calls f with every key that starts with prefix.
+func (c *compiledDictionary) keysWithPrefix(prefix string, f func(k string)) {
+	for i := c.search(prefix); i < c.len(); i++ {
+		k := c.key(i)
+		if !strings.HasPrefix(k, prefix) {
+			break
+		}
+		f(k)
+	}
+}
+
+func (c *compiledDictionary) variants(k string) []int {
+	ns := []int(nil)
+	c.keysWithPrefix(k+"(", func(key string) {
+		if base, n := splitVariant(key); (n > 0) && (base == k) {
+			ns = append(ns, n)
+		}
+	})
+	sort.Ints(ns)
+	return ns
+}
+
+func (c *compiledDictionary) phraseWords(k string) int {
+	most := 0
+	c.keysWithPrefix(k+"_", func(key string) {
+		base, _ := splitVariant(key)
+		if n := strings.Count(base, "_") + 1; most < n {
+			most = n
+		}
+	})
+	return most
+}
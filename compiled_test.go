@@ -0,0 +1,119 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedDictionaryMatchesDefault(t *testing.T) {
+	m, err := NewDefaultDictionary(BritfoneDir)
+	if err != nil {
+		t.Fatalf("NewDefaultDictionary: %v", err)
+	}
+	c, err := NewEmbeddedDictionary()
+	if err != nil {
+		t.Fatalf("NewEmbeddedDictionary: %v", err)
+	}
+
+	if data, err := m.MarshalBinary(); err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	} else if string(data) != embeddedDictionary {
+		t.Fatalf("%s is out of date: run \"go generate\"", CompiledDictionaryFilename)
+	}
+	if got, want := c.Len(), m.Len(); got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+	if got, want := fmt.Sprint(c.Keys()), fmt.Sprint(m.Keys()); got != want {
+		t.Fatalf("Keys differ")
+	}
+
+	for _, k := range m.Keys() {
+		mv, _ := m.Lookup(k)
+		cv, ok := c.Lookup(k)
+		if !ok || (cv != mv) {
+			t.Errorf("Lookup(%q): got %q, %t, want %q", k, cv, ok, mv)
+		}
+		ml, _ := m.Layer(k)
+		if cl, _ := c.Layer(k); cl != ml {
+			t.Errorf("Layer(%q): got %q, want %q", k, cl, ml)
+		}
+
+		base, _ := splitVariant(k)
+		if got, want := fmt.Sprint(c.Variants(base)), fmt.Sprint(m.Variants(base)); got != want {
+			t.Errorf("Variants(%q): got %s, want %s", base, got, want)
+		}
+		if got, want := c.PhraseWords(base), m.PhraseWords(base); got != want {
+			t.Errorf("PhraseWords(%q): got %d, want %d", base, got, want)
+		}
+	}
+	for k := range m.expansions {
+		if got, want := fmt.Sprintf("%q", c.Expansions(k)), fmt.Sprintf("%q", m.Expansions(k)); got != want {
+			t.Errorf("Expansions(%q): got %s, want %s", k, got, want)
+		}
+	}
+
+	for _, k := range []string{"", "A(", "A(0)", "A(99)", "XYZZY", "ZZZZZZ", "\x00", "\xff"} {
+		if v, ok := c.Lookup(k); ok {
+			t.Errorf("Lookup(%q): got %q, want none", k, v)
+		}
+	}
+}
+
+func TestCompiledDictionaryOverrides(t *testing.T) {
+	m := NewDictionary()
+	for k, v := range map[string]string{
+		"CAT":        "k ˈæ t",
+		"DOG":        "d ˈɒ g",
+		"RALEIGH(1)": "ɹ ˈæ l i",
+		"RALEIGH(2)": "ɹ ˈɔː l i",
+	} {
+		if err := m.Add(k, v, LayerBritfone); err != nil {
+			t.Fatalf("Add(%q, %q): %v", k, v, err)
+		}
+	}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	c := NewDictionary()
+	if err := c.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	if err := c.Add("CAT", "k ˈæ t", LayerUser); err == nil {
+		t.Errorf("Add of a compiled key: got nil error")
+	}
+	if err := c.Add("RALEIGH(3)", "ɹ ˈɑː l i", LayerUser); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := c.LoadOverrides(strings.NewReader("DOG, d ˈɔː g\n"), LayerUser); err != nil {
+		t.Fatalf("LoadOverrides: %v", err)
+	}
+
+	testCases := []struct {
+		k, v, layer string
+	}{
+		{"CAT", "k ˈæ t", LayerBritfone},
+		{"DOG", "d ˈɔː g", LayerUser},
+		{"RALEIGH(1)", "ɹ ˈæ l i", LayerBritfone},
+		{"RALEIGH(3)", "ɹ ˈɑː l i", LayerUser},
+	}
+	for _, tc := range testCases {
+		v, _ := c.Lookup(tc.k)
+		layer, _ := c.Layer(tc.k)
+		if (v != tc.v) || (layer != tc.layer) {
+			t.Errorf("%q: got %q, %q, want %q, %q", tc.k, v, layer, tc.v, tc.layer)
+		}
+	}
+	if got, want := fmt.Sprint(c.Variants("RALEIGH")), "[1 2 3]"; got != want {
+		t.Errorf("Variants: got %s, want %s", got, want)
+	}
+	if got, want := c.Len(), 5; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+}
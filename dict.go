@@ -0,0 +1,375 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BritfoneDir is the path, relative to the repository root, of the directory
+// holding the Britfone dictionaries.
+const BritfoneDir = "third-party/Britfone"
+
+// The Britfone dictionaries' filenames, relative to BritfoneDir.
+const (
+	BritfoneMainFilename       = "britfone.main.3.0.1.csv"
+	BritfoneExpansionsFilename = "britfone.expansions.3.0.1.tsv"
+)
+
+// The layers of a Dictionary, in the order that the miileeniol command loads
+// them. An entry in a later layer replaces one in an earlier layer.
+const (
+	LayerBritfone   = "britfone"
+	LayerSupplement = "supplement"
+	LayerProject    = "project"
+	LayerUser       = "user"
+	LayerDocument   = "document"
+)
+
+// Dictionary maps upper-case English words to their space-separated IPA
+// pronunciations, in Britfone's format (e.g. "ð ˈə").
+//
+// A word with more than one pronunciation has numbered keys, such as
+// "RALEIGH(1)" and "RALEIGH(2)". Those are its variants.
+//
+// Each entry comes from a layer, such as LayerBritfone or LayerUser, and
+// loading an override file (see LoadOverrides) replaces earlier entries.
+type Dictionary struct {
+	// compiled, if non-nil, holds the entries of a compiled dictionary, as
+	// per NewEmbeddedDictionary. Entries in the maps below replace them.
+	compiled *compiledDictionary
+
+	m map[string]string
+
+	// layers maps each key to the layer that supplied its entry.
+	layers map[string]string
+
+	// variants maps a word like "RALEIGH" to its sorted variant numbers.
+	variants map[string][]int
+
+	// phrases maps the first word of a multi-word entry like "COSTA_RICA"
+	// to the most words in any entry starting with that word.
+	phrases map[string]int
+
+	// expansions maps abbreviations and symbols, such as "MR." or "&", to
+	// what they expand to, such as "MISTER" or "AMPERSAND".
+	expansions map[string][]string
+}
+
+// NewDictionary returns an empty Dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		m:          map[string]string{},
+		layers:     map[string]string{},
+		variants:   map[string][]int{},
+		phrases:    map[string]int{},
+		expansions: map[string][]string{},
+	}
+}
+
+// NewDefaultDictionary returns a Dictionary holding the Britfone main and
+// expansions dictionaries, loaded from the given directory (typically
+// BritfoneDir), plus this package's supplementary words.
+func NewDefaultDictionary(britfoneDir string) (*Dictionary, error) {
+	d := NewDictionary()
+	if err := d.LoadBritfoneFile(filepath.Join(britfoneDir, BritfoneMainFilename)); err != nil {
+		return nil, err
+	}
+	if err := d.LoadBritfoneExpansionsFile(filepath.Join(britfoneDir, BritfoneExpansionsFilename)); err != nil {
+		return nil, err
+	}
+	for k, v := range supplement {
+		d.set(k, v, LayerSupplement)
+	}
+	return d, nil
+}
+
+// Len returns the number of entries.
+func (d *Dictionary) Len() int {
+	if d.compiled == nil {
+		return len(d.m)
+	}
+	n := d.compiled.len()
+	for k := range d.m {
+		if _, _, ok := d.compiled.lookup(k); !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// Keys returns every key, including numbered variants like "RALEIGH(2)", in
+// sorted order.
+func (d *Dictionary) Keys() []string {
+	keys := make([]string, 0, len(d.m))
+	for k := range d.m {
+		keys = append(keys, k)
+	}
+	if d.compiled != nil {
+		for i, n := 0, d.compiled.len(); i < n; i++ {
+			k := d.compiled.key(i)
+			if _, ok := d.m[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup returns the pronunciation of the upper-case word k.
+func (d *Dictionary) Lookup(k string) (v string, ok bool) {
+	if v, ok = d.m[k]; !ok && (d.compiled != nil) {
+		v, _, ok = d.compiled.lookup(k)
+	}
+	return v, ok
+}
+
+// Layer returns the layer, such as LayerBritfone, that supplied the entry for
+// the key k. Like Lookup, k can be a numbered variant like "RALEIGH(2)".
+func (d *Dictionary) Layer(k string) (layer string, ok bool) {
+	if layer, ok = d.layers[k]; !ok && (d.compiled != nil) {
+		_, layer, ok = d.compiled.lookup(k)
+	}
+	return layer, ok
+}
+
+// Variant returns the pronunciation of the n'th variant of the word k. It is
+// equivalent to looking up "K(N)".
+func (d *Dictionary) Variant(k string, n int) (v string, ok bool) {
+	return d.Lookup(k + "(" + strconv.Itoa(n) + ")")
+}
+
+// Variants returns the variant numbers of the word k, in increasing order.
+// Britfone numbers them from 1 but the numbers are not always contiguous.
+// The result is empty if k has no numbered variants.
+func (d *Dictionary) Variants(k string) []int {
+	if d.compiled == nil {
+		return d.variants[k]
+	}
+	ns := d.compiled.variants(k)
+	if len(d.variants[k]) == 0 {
+		return ns
+	}
+	for _, n := range d.variants[k] {
+		if i := sort.SearchInts(ns, n); (i == len(ns)) || (ns[i] != n) {
+			ns = append(ns, n)
+		}
+	}
+	sort.Ints(ns)
+	return ns
+}
+
+// Expansions returns what the abbreviation or symbol k expands to, most
+// likely first. Each expansion is a space-separated sequence of dictionary
+// keys, such as "MILES PER(1) HOUR".
+func (d *Dictionary) Expansions(k string) []string {
+	return d.expansions[k]
+}
+
+// pickVariant returns the number of the variant of k that policy picks, or 0
+// if k has no variants or policy picks none.
+func (d *Dictionary) pickVariant(k string, policy VariantPolicy) int {
+	ns := d.Variants(k)
+	if len(ns) == 0 {
+		return 0
+	}
+	switch policy {
+	case VariantFirstStressed:
+		for _, n := range ns {
+			if v, _ := d.Variant(k, n); strings.ContainsRune(v, 'ˈ') {
+				return n
+			}
+		}
+		return ns[0]
+	case VariantFirst:
+		return ns[0]
+	}
+	return 0
+}
+
+// Add adds an entry to the given layer. It is an error for k to already be
+// present.
+func (d *Dictionary) Add(k string, v string, layer string) error {
+	if (k == "") || (v == "") {
+		return fmt.Errorf("miileeniol: bad dictionary entry: %q, %q", k, v)
+	}
+	if _, ok := d.Lookup(k); ok {
+		return fmt.Errorf("miileeniol: duplicate dictionary key: %q", k)
+	}
+	d.set(k, v, layer)
+	return nil
+}
+
+// PhraseWords returns the most words in any multi-word entry that starts
+// with the word k. Britfone joins the words with an underscore, as in
+// "COSTA_RICA". It returns 0 if there are no such entries.
+func (d *Dictionary) PhraseWords(k string) int {
+	n := d.phrases[k]
+	if d.compiled != nil {
+		if c := d.compiled.phraseWords(k); n < c {
+			n = c
+		}
+	}
+	return n
+}
+
+func (d *Dictionary) set(k string, v string, layer string) {
+	if _, ok := d.m[k]; !ok {
+		base, n := splitVariant(k)
+		if n > 0 {
+			ns := append(d.variants[base], n)
+			sort.Ints(ns)
+			d.variants[base] = ns
+		}
+		if words := strings.Split(base, "_"); len(words) > 1 {
+			if d.phrases[words[0]] < len(words) {
+				d.phrases[words[0]] = len(words)
+			}
+		}
+	}
+	d.m[k] = v
+	d.layers[k] = layer
+}
+
+// splitVariant splits a key like "RALEIGH(2)" into "RALEIGH" and 2. It
+// returns n == 0 if k has no variant number.
+func splitVariant(k string) (base string, n int) {
+	if !strings.HasSuffix(k, ")") {
+		return k, 0
+	}
+	i := strings.LastIndexByte(k, '(')
+	if i <= 0 {
+		return k, 0
+	}
+	n, err := strconv.Atoi(k[i+1 : len(k)-1])
+	if (err != nil) || (n <= 0) {
+		return k, 0
+	}
+	return k[:i], n
+}
+
+// LoadBritfoneFile is like LoadBritfone but reads from the named file.
+func (d *Dictionary) LoadBritfoneFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return d.LoadBritfone(f)
+}
+
+// LoadBritfone adds the entries of a Britfone main dictionary, one
+// "WORD, pronunciation" per line.
+func (d *Dictionary) LoadBritfone(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Bytes()
+
+		if i := bytes.IndexByte(line, ','); i >= 0 {
+			k, v := string(line[:i]), strings.TrimSpace(string(line[i+1:]))
+			if (k == "") || (v == "") {
+				return fmt.Errorf("miileeniol: bad Britfone line: %q", line)
+			}
+			if _, ok := d.Lookup(k); ok {
+				return fmt.Errorf("miileeniol: duplicate Britfone key: %q", k)
+			}
+			d.set(k, v, LayerBritfone)
+
+		} else if _, ok := d.Lookup(string(line)); ok {
+			return fmt.Errorf("miileeniol: duplicate Britfone key: %q", line)
+		}
+	}
+	return s.Err()
+}
+
+// LoadOverridesFile is like LoadOverrides but reads from the named file.
+func (d *Dictionary) LoadOverridesFile(filename string, layer string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := d.LoadOverrides(f, layer); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+// LoadOverrides adds the entries of an override dictionary to the given
+// layer, replacing any existing entries for the same keys.
+//
+// The format is Britfone's, one "WORD, pronunciation" per line, except that
+// blank lines and lines starting with '#' are ignored and words are
+// upper-cased. Words can be numbered variants like "READ(2)", phrases like
+// "COSTA_RICA" or marked heteronyms like "LEAD%E". For example:
+//
+//	# Say "tomato" the other way.
+//	TOMATO, t ə m ˈeɪ t əʊ
+func (d *Dictionary) LoadOverrides(r io.Reader, layer string) error {
+	seen := map[string]bool{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if (text == "") || (text[0] == '#') {
+			continue
+		}
+		i := strings.IndexByte(text, ',')
+		if i < 0 {
+			return fmt.Errorf("miileeniol: line %d: missing comma: %q", line, text)
+		}
+		k, v := strings.ToUpper(strings.TrimSpace(text[:i])), strings.Join(strings.Fields(text[i+1:]), " ")
+		if (k == "") || (v == "") {
+			return fmt.Errorf("miileeniol: line %d: bad entry: %q", line, text)
+		} else if seen[k] {
+			return fmt.Errorf("miileeniol: line %d: duplicate key: %q", line, k)
+		}
+		seen[k] = true
+		d.set(k, v, layer)
+	}
+	return s.Err()
+}
+
+// LoadBritfoneExpansionsFile is like LoadBritfoneExpansions but reads from
+// the named file.
+func (d *Dictionary) LoadBritfoneExpansionsFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return d.LoadBritfoneExpansions(f)
+}
+
+// LoadBritfoneExpansions adds the entries of a Britfone expansions
+// dictionary, one tab-separated "ABBREVIATION\tEXPANSION" per line. An
+// abbreviation can have more than one line, most likely first.
+func (d *Dictionary) LoadBritfoneExpansions(r io.Reader) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		i := bytes.IndexByte(line, '\t')
+		if i < 0 {
+			return fmt.Errorf("miileeniol: bad Britfone expansions line: %q", line)
+		}
+		k, v := string(line[:i]), strings.TrimSpace(string(line[i+1:]))
+		if (k == "") || (v == "") {
+			return fmt.Errorf("miileeniol: bad Britfone expansions line: %q", line)
+		}
+		d.expansions[k] = append(d.expansions[k], v)
+	}
+	return s.Err()
+}
@@ -0,0 +1,11 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+// Package miileeniol transliterates English text to Miileeniol, an English
+// phonetic alphabet, and renders it.
+//
+// A Dictionary maps English words to IPA pronunciations. An Alphabet maps
+// those pronunciations' phonemes to letters. A Transliterator combines the
+// two, and a Renderer draws a Transliterator's output as an image.
+package miileeniol
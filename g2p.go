@@ -0,0 +1,472 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+)
+
+// G2P guesses the pronunciation of words that are not in a Dictionary: it
+// converts graphemes (letters) to phonemes.
+//
+// It is trained on a Dictionary in two steps. First, each word's letters are
+// aligned with its phonemes, each letter producing zero, one or two phonemes
+// (a chunk), by hard expectation maximization. Second, for each letter and
+// its neighbors (up to 3 letters either side), the most common chunk is
+// remembered. Guessing a word picks, for each letter, the chunk for the
+// widest context seen in training. Stress marks are part of a chunk's vowels,
+// so they are guessed along with the phonemes, and then fixed up so that
+// every guess has exactly one primary stress.
+//
+// A G2P is safe for concurrent use.
+type G2P struct {
+	once       sync.Once
+	dictionary *Dictionary
+
+	// chunks maps a context key (see g2pContextKey) to its most common
+	// chunk: a space-separated sequence of Britfone phonemes.
+	chunks map[string]string
+}
+
+// g2pContexts are the (left, right) context widths, widest first.
+var g2pContexts = [...][2]int{
+	{3, 3}, {2, 3}, {3, 2}, {2, 2}, {1, 2}, {2, 1}, {1, 1}, {0, 1}, {1, 0}, {0, 0},
+}
+
+const g2pPad = "###"
+
+// g2pExample is a training example: an upper-case word and its phonemes,
+// with and without stress marks.
+type g2pExample struct {
+	word     string
+	phonemes []string
+	bases    []string
+}
+
+// NewG2P returns a G2P trained on the words in d. Training takes a moment,
+// so it is done when the G2P is first used. d should not be modified after
+// NewG2P is called.
+func NewG2P(d *Dictionary) *G2P {
+	return &G2P{dictionary: d}
+}
+
+// Guess returns the guessed pronunciation, in Britfone's format, of an
+// upper-case word. It returns "" if the word has no letters or has
+// characters other than A-Z, apostrophes and hyphens.
+func (g *G2P) Guess(word string) string {
+	if !isG2PWord(word) {
+		return ""
+	}
+	g.once.Do(func() {
+		if g.dictionary != nil {
+			g.chunks = trainChunks(g2pExamples(g.dictionary))
+		}
+	})
+	padded := g2pPad + word + g2pPad
+	phonemes := []string(nil)
+	for i := range word {
+		for _, c := range g2pContexts {
+			if chunk, ok := g.chunks[g2pContextKey(padded, len(g2pPad)+i, c)]; ok {
+				if chunk != "" {
+					phonemes = append(phonemes, strings.Split(chunk, " ")...)
+				}
+				break
+			}
+		}
+	}
+	if len(phonemes) == 0 {
+		return ""
+	}
+	fixStress(phonemes)
+	return strings.Join(phonemes, " ")
+}
+
+func isG2PWord(word string) bool {
+	seenLetter := false
+	for i := 0; i < len(word); i++ {
+		if c := word[i]; ('A' <= c) && (c <= 'Z') {
+			seenLetter = true
+		} else if (c != '\'') && (c != '-') {
+			return false
+		}
+	}
+	return seenLetter
+}
+
+// g2pContextKey returns the key for the letter at padded[i] with c's left
+// and right context widths.
+func g2pContextKey(padded string, i int, c [2]int) string {
+	return string(rune('0'+c[0])) + string(rune('0'+c[1])) + padded[i-c[0]:i+1+c[1]]
+}
+
+// g2pExamples returns the dictionary words that a G2P can train on, sorted
+// by word. A word with numbered variants contributes the variant that
+// VariantFirstStressed picks.
+func g2pExamples(d *Dictionary) []g2pExample {
+	examples := []g2pExample(nil)
+	for _, k := range d.Keys() {
+		base, n := splitVariant(k)
+		if !isG2PWord(base) {
+			continue
+		} else if n == 0 {
+			if len(d.Variants(base)) > 0 {
+				continue
+			}
+		} else if _, ok := d.Lookup(base); ok || (n != d.pickVariant(base, VariantFirstStressed)) {
+			continue
+		}
+		v, _ := d.Lookup(k)
+		phonemes := strings.Fields(v)
+		bases := make([]string, len(phonemes))
+		for i, p := range phonemes {
+			bases[i] = stripStress(p)
+		}
+		examples = append(examples, g2pExample{base, phonemes, bases})
+	}
+	return examples
+}
+
+// trainChunks returns the chunks field of a G2P trained on examples.
+func trainChunks(examples []g2pExample) map[string]string {
+	alignments := alignG2P(examples)
+
+	// Count each context's chunks, interning the chunks as small integers.
+	chunks, chunkIDs := []string(nil), map[string]int{}
+	keys, keyIndexes, counts := []string(nil), make(map[string]int, 1<<19), [][]int(nil)
+	for i, ex := range examples {
+		alignment := alignments[i]
+		if alignment == nil {
+			continue
+		}
+		padded := g2pPad + ex.word + g2pPad
+		for j, chunk := range alignment {
+			id, ok := chunkIDs[chunk]
+			if !ok {
+				id = len(chunks)
+				chunks = append(chunks, chunk)
+				chunkIDs[chunk] = id
+			}
+			for _, c := range g2pContexts {
+				key := g2pContextKey(padded, len(g2pPad)+j, c)
+				k, ok := keyIndexes[key]
+				if !ok {
+					k = len(keys)
+					keys = append(keys, key)
+					counts = append(counts, nil)
+					keyIndexes[key] = k
+				}
+				for len(counts[k]) <= id {
+					counts[k] = append(counts[k], 0)
+				}
+				counts[k][id]++
+			}
+		}
+	}
+
+	bestChunks := make(map[string]string, len(keys))
+	for k, key := range keys {
+		m, best := counts[k], 0
+		for id, count := range m {
+			if (count > m[best]) || ((count == m[best]) && (chunks[id] < chunks[best])) {
+				best = id
+			}
+		}
+		bestChunks[key] = chunks[best]
+	}
+	return bestChunks
+}
+
+// alignG2P aligns each example's letters with its phonemes, returning one
+// chunk (of zero, one or two phonemes, with stress marks) per letter. The
+// alignment is nil for examples with too many phonemes to align.
+func alignG2P(examples []g2pExample) [][]string {
+	// Intern every chunk of unstressed phonemes: chunkIDs[i][j][n] is the ID
+	// of the n phonemes of examples[i] starting at the j'th phoneme.
+	ids := map[string]int{"": 0}
+	chunkIDs := make([][][3]int, len(examples))
+	for i, ex := range examples {
+		chunkIDs[i] = make([][3]int, len(ex.bases)+1)
+		for j := range chunkIDs[i] {
+			for n := 1; (n <= 2) && (j+n <= len(ex.bases)); n++ {
+				chunk := strings.Join(ex.bases[j:j+n], " ")
+				id, ok := ids[chunk]
+				if !ok {
+					id = len(ids)
+					ids[chunk] = id
+				}
+				chunkIDs[i][j][n] = id
+			}
+		}
+	}
+
+	// counts[letter][id] is how often a letter produces a chunk. The initial
+	// counts pair each letter with the phonemes at roughly the same relative
+	// position in its word.
+	counts := make([][]float64, 256)
+	for i := range counts {
+		counts[i] = make([]float64, len(ids))
+	}
+	for i, ex := range examples {
+		nl, np := len(ex.word), len(ex.bases)
+		for k := 0; k < nl; k++ {
+			counts[ex.word[k]][0] += 0.5
+			j0, j1 := (k*np)/nl-1, ((k+1)*np+nl-1)/nl+1
+			for j := j0; j < j1; j++ {
+				if (0 <= j) && (j < np) {
+					counts[ex.word[k]][chunkIDs[i][j][1]]++
+				}
+			}
+		}
+	}
+
+	alignments := make([][]string, len(examples))
+	scores := make([][]float64, 256)
+	for i := range scores {
+		scores[i] = make([]float64, len(ids))
+	}
+	for iteration := 0; iteration < 5; iteration++ {
+		for letter, m := range counts {
+			total := 0.0
+			for _, n := range m {
+				total += n
+			}
+			for id, n := range m {
+				scores[letter][id] = math.Log((n + 0.1) / (total + 10))
+			}
+			for id := range m {
+				m[id] = 0
+			}
+		}
+
+		for i, ex := range examples {
+			chunkLens := viterbiG2P(ex, chunkIDs[i], scores)
+			if chunkLens == nil {
+				alignments[i] = nil
+				continue
+			}
+			alignment := make([]string, len(chunkLens))
+			j := 0
+			for k, n := range chunkLens {
+				counts[ex.word[k]][chunkIDs[i][j][n]]++
+				alignment[k] = strings.Join(ex.phonemes[j:j+n], " ")
+				j += n
+			}
+			alignments[i] = alignment
+		}
+	}
+	return alignments
+}
+
+// viterbiG2P returns the most likely number of phonemes (0, 1 or 2) produced
+// by each of ex's letters, given the chunk IDs and per-letter chunk scores
+// (log probabilities) computed by alignG2P.
+func viterbiG2P(ex g2pExample, chunkIDs [][3]int, scores [][]float64) []int {
+	nl, np := len(ex.word), len(ex.bases)
+	if np > 2*nl {
+		return nil
+	}
+	negInf := math.Inf(-1)
+	// best[i][j] is the best score for aligning the first i letters with the
+	// first j phonemes, and from[i][j] is the last chunk's length.
+	best := make([][]float64, nl+1)
+	from := make([][]int, nl+1)
+	for i := range best {
+		best[i] = make([]float64, np+1)
+		from[i] = make([]int, np+1)
+		for j := range best[i] {
+			best[i][j] = negInf
+		}
+	}
+	best[0][0] = 0
+	for i := 0; i < nl; i++ {
+		letterScores := scores[ex.word[i]]
+		for j := 0; j <= np; j++ {
+			if best[i][j] == negInf {
+				continue
+			}
+			for n := 0; (n <= 2) && (j+n <= np); n++ {
+				s := best[i][j] + letterScores[chunkIDs[j][n]]
+				if s > best[i+1][j+n] {
+					best[i+1][j+n] = s
+					from[i+1][j+n] = n
+				}
+			}
+		}
+	}
+	if best[nl][np] == negInf {
+		return nil
+	}
+	chunkLens := make([]int, nl)
+	for i, j := nl, np; i > 0; i-- {
+		n := from[i][j]
+		chunkLens[i-1] = n
+		j -= n
+	}
+	return chunkLens
+}
+
+// fixStress modifies phonemes so that exactly one carries the primary stress
+// mark. Extra primary stresses are demoted to secondary. If there is none,
+// the first secondary stress is promoted, or else the first full vowel (not
+// a schwa-like "ə", "ɪ" or "i") is stressed, or else the first vowel.
+func fixStress(phonemes []string) {
+	primary := -1
+	for i, p := range phonemes {
+		if !strings.HasPrefix(p, "ˈ") {
+			continue
+		} else if primary < 0 {
+			primary = i
+		} else {
+			phonemes[i] = "ˌ" + strings.TrimPrefix(p, "ˈ")
+		}
+	}
+	if primary >= 0 {
+		return
+	}
+
+	candidate := -1
+	for i, p := range phonemes {
+		if strings.HasPrefix(p, "ˌ") {
+			candidate = i
+			break
+		}
+	}
+	if candidate < 0 {
+		for i, p := range phonemes {
+			if isVowelPhoneme(p) && (p != "ə") && (p != "ɪ") && (p != "i") {
+				candidate = i
+				break
+			}
+		}
+	}
+	if candidate < 0 {
+		for i, p := range phonemes {
+			if isVowelPhoneme(p) {
+				candidate = i
+				break
+			}
+		}
+	}
+	if candidate >= 0 {
+		phonemes[candidate] = "ˈ" + stripStress(phonemes[candidate])
+	}
+}
+
+func stripStress(phoneme string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(phoneme, "ˈ"), "ˌ")
+}
+
+func isVowelPhoneme(phoneme string) bool {
+	return strings.ContainsAny(phoneme, "aeiouæɐɑɒɔəɛɜɪʊ")
+}
+
+// G2PAccuracy is how well a G2P guesses held-out dictionary words.
+type G2PAccuracy struct {
+	// Words is the number of held-out words.
+	Words int
+
+	// Correct is the number of words guessed exactly, including stress.
+	// CorrectIgnoringStress ignores stress marks.
+	Correct               int
+	CorrectIgnoringStress int
+
+	// Phonemes is the total number of phonemes in the held-out words.
+	// PhonemeErrors is the total edit distance (ignoring stress) between the
+	// guesses and the dictionary.
+	Phonemes      int
+	PhonemeErrors int
+}
+
+// EvaluateG2P trains a G2P on about all but one in n of the trainable words
+// of d, and measures how well it guesses the held-out words. Words are held
+// out by their stem, as per g2pStem, so that a held-out "DOGS" can't be
+// guessed from a trained "DOG". Which stems are held out depends only on
+// the stems, not on the rest of d.
+func EvaluateG2P(d *Dictionary, n int) G2PAccuracy {
+	if n < 2 {
+		n = 2
+	}
+	training, heldOut := []g2pExample(nil), []g2pExample(nil)
+	for _, ex := range g2pExamples(d) {
+		h := fnv.New32a()
+		h.Write([]byte(g2pStem(ex.word)))
+		if (h.Sum32() % uint32(n)) == 0 {
+			heldOut = append(heldOut, ex)
+		} else {
+			training = append(training, ex)
+		}
+	}
+	g := &G2P{chunks: trainChunks(training)}
+
+	a := G2PAccuracy{}
+	for _, ex := range heldOut {
+		guess := strings.Fields(g.Guess(ex.word))
+		guessBases := make([]string, len(guess))
+		for i, p := range guess {
+			guessBases[i] = stripStress(p)
+		}
+
+		a.Words++
+		a.Phonemes += len(ex.bases)
+		if strings.Join(guess, " ") == strings.Join(ex.phonemes, " ") {
+			a.Correct++
+		}
+		if e := editDistance(guessBases, ex.bases); e == 0 {
+			a.CorrectIgnoringStress++
+		} else {
+			a.PhonemeErrors += e
+		}
+	}
+	return a
+}
+
+// g2pStem returns a word's stem, such as "DOG" for "DOGS" or "DIM" for
+// "DIMMED", by removing an inflectional suffix, a doubled final consonant
+// and a final "E".
+func g2pStem(word string) string {
+	word = strings.TrimSuffix(word, "'S")
+	for _, suffix := range [...]string{"INGS", "ING", "EDLY", "ED", "ERS", "ER", "EST", "LY", "ES", "S"} {
+		if s := strings.TrimSuffix(word, suffix); (len(s) < len(word)) && (len(s) >= 3) {
+			word = s
+			break
+		}
+	}
+	if n := len(word); (n >= 4) && (word[n-1] == word[n-2]) && isConsonantLetter(word[n-1]) {
+		word = word[:n-1]
+	}
+	if s := strings.TrimSuffix(word, "E"); len(s) >= 3 {
+		word = s
+	}
+	return word
+}
+
+func editDistance(a []string, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			c := prev[j-1]
+			if a[i-1] != b[j-1] {
+				c++
+			}
+			if x := prev[j] + 1; c > x {
+				c = x
+			}
+			if x := curr[j-1] + 1; c > x {
+				c = x
+			}
+			curr[j] = c
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -1,8 +1,9 @@
 module github.com/nigeltao/miileeniol
 
-go 1.16
+go 1.17
 
 require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	golang.org/x/image v0.0.0-20201208152932-35266b937fa6
+	golang.org/x/text v0.13.0
 )
@@ -0,0 +1,332 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strconv"
+	"strings"
+)
+
+// wordClass is a heteronym's part of speech, as guessed from its context.
+type wordClass int
+
+const (
+	classNone wordClass = iota
+	classNoun
+	classVerb
+	classPast
+	classAdj
+
+	// classComplement is a word after a form of "BE", which is a past
+	// participle, as in "was read", or an adjective, as in "is close".
+	classComplement
+)
+
+// classFallbacks are the classes to try, in order, for a guessed class.
+var classFallbacks = map[wordClass][]wordClass{
+	classNoun:       {classNoun, classAdj},
+	classVerb:       {classVerb, classAdj},
+	classPast:       {classPast, classVerb},
+	classAdj:        {classAdj, classNoun},
+	classComplement: {classPast, classAdj, classNoun},
+}
+
+func (c wordClass) String() string {
+	switch c {
+	case classNoun:
+		return "noun"
+	case classVerb:
+		return "verb"
+	case classPast:
+		return "past participle"
+	case classAdj:
+		return "adjective"
+	}
+	return "unknown"
+}
+
+// heteronym is which of a word's Britfone variants each wordClass picks.
+// Zero means none. If stressShift is set, a noun picks the variant whose
+// primary stress is earliest, as in "REcord", a verb picks the one whose
+// primary stress is latest, as in "reCORD", and an adjective picks the one
+// given by shiftAdj, if any.
+type heteronym struct {
+	noun, verb, past, adj int
+	stressShift           bool
+	shiftAdj              shiftAdj
+}
+
+// shiftAdj is where a stressShift heteronym's adjective has its primary
+// stress.
+type shiftAdj int
+
+const (
+	noShiftAdj shiftAdj = iota
+	earlyShiftAdj
+	lateShiftAdj
+)
+
+// heteronyms are the words that Disambiguate knows about.
+var heteronyms = map[string]heteronym{
+	"ABUSE":  {noun: 2, verb: 1},
+	"BOW":    {noun: 1, verb: 2},
+	"CLOSE":  {verb: 2, adj: 1},
+	"EXCUSE": {noun: 1, verb: 2},
+	"LEAD":   {noun: 2, verb: 1},
+	"LIVE":   {verb: 1, adj: 2},
+	"LIVES":  {noun: 2, verb: 1},
+	"READ":   {noun: 1, verb: 1, past: 2},
+	"TEAR":   {noun: 1, verb: 2},
+	"TEARS":  {noun: 1, verb: 2},
+	"USE":    {noun: 1, verb: 2},
+	"WIND":   {noun: 1, verb: 2},
+	"WINDS":  {noun: 1, verb: 2},
+	"WOUND":  {noun: 1, verb: 1, past: 2},
+
+	"CONDUCT":  {stressShift: true},
+	"CONFLICT": {stressShift: true},
+	"CONTENT":  {stressShift: true, shiftAdj: lateShiftAdj},
+	"CONTRACT": {stressShift: true},
+	"CONVICT":  {stressShift: true},
+	"DECREASE": {stressShift: true},
+	"DESERT":   {stressShift: true},
+	"INCREASE": {stressShift: true},
+	"OBJECT":   {stressShift: true},
+	"PERMIT":   {stressShift: true},
+	"PRESENT":  {stressShift: true, shiftAdj: earlyShiftAdj},
+	"PRODUCE":  {stressShift: true},
+	"PROJECT":  {stressShift: true},
+	"REBEL":    {stressShift: true},
+	"RECORD":   {stressShift: true},
+	"RECORDS":  {stressShift: true},
+	"REFUSE":   {stressShift: true},
+	"SUSPECT":  {stressShift: true, shiftAdj: earlyShiftAdj},
+}
+
+// Context words. Adverbs are skipped over when looking at the previous word,
+// as in "can still read".
+var (
+	haveWords = wordSet("HAS HAVE HAD HAVING")
+	beWords   = wordSet("AM IS ARE WAS WERE BE BEEN BEING")
+	verbWords = wordSet("CAN COULD WILL WOULD SHALL SHOULD MAY MIGHT MUST TO " +
+		"DO DOES DID DON'T DOESN'T DIDN'T CAN'T WON'T NOT I YOU WE THEY HE SHE WHO")
+	nounWords = wordSet("A AN THE THESE THOSE MY YOUR HIS HER ITS OUR THEIR " +
+		"SOME ANY NO EVERY EACH OF IN ON AT WITH FOR FROM BY INTO")
+	objectWords = wordSet("IT THEM HIM ME US THE A AN EVERY THIS THESE " +
+		"MY YOUR HIS OUR THEIR")
+	prepositionWords = wordSet("OF IN ON AT WITH FOR FROM BY INTO")
+	adverbWords      = wordSet("STILL ALSO JUST NEVER ALWAYS OFTEN EVEN ONLY SOON")
+
+	// nounEndWords can follow a noun phrase, unlike another noun, as in "the
+	// lead singer", which a noun before it would modify.
+	nounEndWords = wordSet("OF IN ON AT WITH FOR FROM BY INTO AND OR BUT THAT WHICH " +
+		"AM IS ARE WAS WERE HAS HAVE HAD CAN COULD WILL WOULD SHALL SHOULD MAY MIGHT MUST")
+)
+
+func wordSet(words string) map[string]bool {
+	m := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		m[w] = true
+	}
+	return m
+}
+
+// Decision is how Disambiguate picked a heteronym's pronunciation.
+type Decision struct {
+	// Line is the 1-based line number in the text.
+	Line int
+
+	// Word is the upper-cased word, such as "LEAD".
+	Word string
+
+	// Variant is the picked variant number, as in "LEAD(2)", or zero if the
+	// context didn't pick one and the word was left unmarked, to be read as
+	// its default variant.
+	Variant int
+
+	Pronunciation string
+
+	// Reason describes the context, such as `verb after "MIGHT"`.
+	Reason string
+}
+
+// Disambiguate picks the pronunciation of heteronyms such as "lead" (the
+// verb or the metal) from their context: the neighboring words hint at
+// their part of speech. It returns text with a variant marker, such as
+// "%2", appended to each such heteronym, and the decisions made.
+//
+// Words that already have a marker, such as "lead%e", are left alone, as are
+// words whose variants are overridden by a plain dictionary entry.
+func (t *Transliterator) Disambiguate(text string) (marked string, decisions []Decision) {
+	sb := strings.Builder{}
+	for lineNumber, line := range strings.Split(text, "\n") {
+		if lineNumber > 0 {
+			sb.WriteByte('\n')
+		}
+
+		type token struct {
+			key, suffix string
+			end         int
+		}
+		tokens := []token(nil)
+		for s := line; s != ""; {
+			if s[0] <= ' ' {
+				s = s[1:]
+				continue
+			}
+			word, remaining := Parse(s)
+			key, suffix := splitSuffix(word)
+			tokens = append(tokens, token{key, suffix, len(line) - len(remaining) - len(suffix)})
+			s = remaining
+		}
+
+		prev := 0
+		for i, tok := range tokens {
+			h, ok := heteronyms[tok.key]
+			if !ok || strings.HasPrefix(tok.suffix, "%") || t.hasPlainEntry(tok.key) {
+				continue
+			}
+			before, after := "", ""
+			for j := i - 1; j >= 0; j-- {
+				if (tokens[j].key == "") || strings.ContainsAny(tokens[j].suffix, ".!?;:") {
+					break
+				} else if !adverbWords[tokens[j].key] && !strings.HasSuffix(tokens[j].key, "LY") {
+					before = tokens[j].key
+					break
+				}
+			}
+			if (i+1 < len(tokens)) && !strings.ContainsAny(tok.suffix, ",.!?;:") {
+				after = tokens[i+1].key
+			}
+
+			class, reason := classify(before, after)
+			n, class := t.heteronymVariant(tok.key, h, class)
+			if n == 0 {
+				if reason == "" {
+					reason = "no context; default variant"
+				} else {
+					reason = class.String() + " " + reason + "; default variant"
+				}
+				decisions = append(decisions, Decision{
+					Line:   lineNumber + 1,
+					Word:   tok.key,
+					Reason: reason,
+				})
+				continue
+			}
+			v, _ := t.Dictionary.Variant(tok.key, n)
+			decisions = append(decisions, Decision{
+				Line:          lineNumber + 1,
+				Word:          tok.key,
+				Variant:       n,
+				Pronunciation: v,
+				Reason:        class.String() + " " + reason,
+			})
+			sb.WriteString(line[prev:tok.end])
+			sb.WriteString("%" + strconv.Itoa(n))
+			prev = tok.end
+		}
+		sb.WriteString(line[prev:])
+	}
+	return sb.String(), decisions
+}
+
+// hasPlainEntry returns whether k has an unnumbered dictionary entry.
+func (t *Transliterator) hasPlainEntry(k string) bool {
+	_, ok := t.Dictionary.Lookup(k)
+	return ok
+}
+
+// classify guesses a word's class from the (upper-case) words before and
+// after it, either of which can be "".
+func classify(before string, after string) (wordClass, string) {
+	switch {
+	case haveWords[before] || strings.HasSuffix(before, "'VE"):
+		return classPast, "after " + strconv.Quote(before)
+	case beWords[before] || strings.HasSuffix(before, "'RE"):
+		return classComplement, "after " + strconv.Quote(before)
+	case verbWords[before] || strings.HasSuffix(before, "'LL") || strings.HasSuffix(before, "'D"):
+		return classVerb, "after " + strconv.Quote(before)
+	case nounWords[before] || strings.HasSuffix(before, "'S"):
+		if (after != "") && !nounEndWords[after] {
+			return classNone, "between " + strconv.Quote(before) + " and " + strconv.Quote(after)
+		}
+		return classNoun, "after " + strconv.Quote(before)
+	case objectWords[after]:
+		return classVerb, "before " + strconv.Quote(after)
+	case prepositionWords[after]:
+		return classNoun, "before " + strconv.Quote(after)
+	}
+	return classNone, ""
+}
+
+// heteronymVariant returns the variant of the word k that class picks, and
+// the class that picked it, as per classFallbacks.
+func (t *Transliterator) heteronymVariant(k string, h heteronym, class wordClass) (int, wordClass) {
+	if h.stressShift {
+		earliest, latest := 0, 0
+		earliestPos, latestPos := 0, 0
+		for _, n := range t.Dictionary.Variants(k) {
+			v, _ := t.Dictionary.Variant(k, n)
+			pos := stressPosition(v)
+			if (earliest == 0) || (pos < earliestPos) {
+				earliest, earliestPos = n, pos
+			}
+			if (latest == 0) || (pos > latestPos) {
+				latest, latestPos = n, pos
+			}
+		}
+		if earliestPos == latestPos {
+			return 0, classNone
+		}
+		adj := 0
+		switch h.shiftAdj {
+		case earlyShiftAdj:
+			adj = earliest
+		case lateShiftAdj:
+			adj = latest
+		case noShiftAdj:
+			// After a form of "BE", the noun is rarely meant, as in "is
+			// content", so leave a word that isn't an adjective unresolved.
+			if class == classComplement {
+				return 0, classNone
+			}
+		}
+		h = heteronym{noun: earliest, verb: latest, adj: adj}
+	}
+
+	for _, c := range classFallbacks[class] {
+		n := 0
+		switch c {
+		case classNoun:
+			n = h.noun
+		case classVerb:
+			n = h.verb
+		case classPast:
+			n = h.past
+		case classAdj:
+			n = h.adj
+		}
+		if n > 0 {
+			if _, ok := t.Dictionary.Variant(k, n); ok {
+				return n, c
+			}
+		}
+	}
+	return 0, classNone
+}
+
+// stressPosition returns the number of vowels before the primary stress.
+func stressPosition(pronunciation string) int {
+	n := 0
+	for _, p := range strings.Fields(pronunciation) {
+		if strings.HasPrefix(p, "ˈ") {
+			return n
+		} else if isVowelPhoneme(p) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDisambiguate(t *testing.T) {
+	d := NewDictionary()
+	for k, v := range map[string]string{
+		"CONTENT(1)": "k ə n t ˈɛ n t",
+		"CONTENT(2)": "k ˈɒ n t ɛ n t",
+		"LEAD(1)":    "l ˈiː d",
+		"LEAD(2)":    "l ˈɛ d",
+		"READ(1)":    "ɹ ˈiː d",
+		"READ(2)":    "ɹ ˈɛ d",
+		"RECORD(1)":  "ɹ ˈɛ k ɔː d",
+		"RECORD(2)":  "ɹ ɪ k ˈɔː d",
+		"WIND":       "w ˈɪ n d",
+	} {
+		if err := d.Add(k, v, LayerUser); err != nil {
+			t.Fatalf("Add(%q, %q): %v", k, v, err)
+		}
+	}
+	tr := &Transliterator{Dictionary: d}
+
+	testCases := []struct {
+		text      string
+		marked    string
+		decisions string
+	}{
+		// Nouns.
+		{"the lead pipe.", "the lead pipe.", `1 LEAD%0 unknown between "THE" and "PIPE"; default variant`},
+		{"the lead is heavy", "the lead%2 is heavy", `1 LEAD%2 noun after "THE"`},
+		{"a record of it", "a record%1 of it", `1 RECORD%1 noun after "A"`},
+
+		// Verbs.
+		{"they lead the band", "they lead%1 the band", `1 LEAD%1 verb after "THEY"`},
+		{"we will record it", "we will record%2 it", `1 RECORD%2 verb after "WILL"`},
+		{"I can still read", "I can still read%1", `1 READ%1 verb after "CAN"`},
+
+		// Past participles and complements.
+		{"it has read", "it has read%2", `1 READ%2 past participle after "HAS"`},
+		{"it was read", "it was read%2", `1 READ%2 past participle after "WAS"`},
+		{"She is content.", "She is content%1.", `1 CONTENT%1 adjective after "IS"`},
+		{"It is record.", "It is record.", `1 RECORD%0 unknown after "IS"; default variant`},
+
+		// No context.
+		{"Lead is heavy.", "Lead is heavy.", `1 LEAD%0 no context; default variant`},
+
+		// Explicit markers and words without variants are left alone.
+		{"the lead%1 singer", "the lead%1 singer", ""},
+		{"the wind", "the wind", ""},
+
+		// Lines are numbered.
+		{"Lead\nthey lead", "Lead\nthey lead%1", "1 LEAD%0 no context; default variant\n" +
+			`2 LEAD%1 verb after "THEY"`},
+	}
+	for _, tc := range testCases {
+		marked, decisions := tr.Disambiguate(tc.text)
+		got := []string(nil)
+		for _, dec := range decisions {
+			got = append(got, fmt.Sprintf("%d %s%%%d %s", dec.Line, dec.Word, dec.Variant, dec.Reason))
+		}
+		if marked != tc.marked {
+			t.Errorf("Disambiguate(%q): got %q, want %q", tc.text, marked, tc.marked)
+		}
+		if g := strings.Join(got, "\n"); g != tc.decisions {
+			t.Errorf("Disambiguate(%q): got decisions %q, want %q", tc.text, g, tc.decisions)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WordMarker, appended to an upper-case word in the input text, as in
+// "NATO%W", means that the word is read as a word, not as an initialism.
+const WordMarker = "%W"
+
+// letterNames are the dictionary keys for the names of the letters A to Z.
+var letterNames = [26]string{
+	"A(2)", "BEE", "CEE", "DEE", "E", "EF", "GEE", "AITCH", "I", "JAY", "KAY",
+	"EL", "EM", "EN", "O", "PEE", "CUE", "AR", "ESS", "TEE", "U", "VEE",
+	"DOUBLE U", "ECKS", "WY", "ZED",
+}
+
+// initialism returns the letters of s, the original text of a word, if it
+// looks like an initialism: two or more upper-case letters, optionally
+// followed by a plural "s" or a possessive "'s". The plural or possessive
+// is returned separately, upper-cased.
+func initialism(s string) (letters string, plural string) {
+	// Trim s itself, as upper-casing can change its length.
+	s = strings.TrimRightFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, p := range [...]string{"'s", "’s", "s"} {
+		if strings.HasSuffix(s, p) {
+			s, plural = s[:len(s)-len(p)], strings.ToUpper(p)
+			break
+		}
+	}
+	if len(s) < 2 {
+		return "", ""
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < 'A') || ('Z' < c) {
+			return "", ""
+		}
+	}
+	return s, plural
+}
+
+// transliterateInitialism transliterates englishWord, whose original text is
+// s, as the names of its letters, as in "BBC" /ˌbiːˌbiːˈsiː/, if it is an
+// initialism that isn't in the dictionary and can't be derived from a word
+// that is. Only the last letter keeps its primary stress.
+func (t *Transliterator) transliterateInitialism(englishWord string, s string) (w Word, ok bool, err error) {
+	letters, plural := initialism(s)
+	if letters == "" {
+		return Word{}, false, nil
+	}
+	dictKey, suffix := splitSuffix(englishWord)
+	if t.hasEntry(dictKey) {
+		return Word{}, false, nil
+	} else if t.Derive {
+		if _, ok := t.derive(dictKey, maxDeriveDepth); ok {
+			return Word{}, false, nil
+		}
+	}
+
+	phonemes := []string(nil)
+	for i := 0; i < len(letters); i++ {
+		for _, k := range strings.Fields(letterNames[letters[i]-'A']) {
+			v, ok := t.Dictionary.Lookup(k)
+			if base, n := splitVariant(k); n > 0 {
+				v, ok = t.Dictionary.Variant(base, n)
+			}
+			if !ok {
+				return Word{}, false, nil
+			}
+			phonemes = append(phonemes, strings.Fields(v)...)
+		}
+	}
+	last := -1
+	for i, p := range phonemes {
+		if strings.HasPrefix(p, "ˈ") {
+			if last >= 0 {
+				phonemes[last] = "ˌ" + strings.TrimPrefix(phonemes[last], "ˈ")
+			}
+			last = i
+		}
+	}
+	if plural != "" {
+		phonemes = inflectS(phonemes, letters)
+	}
+	w, err = t.spell(Word{English: englishWord}, strings.Join(phonemes, " "), suffix)
+	return w, true, err
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"testing"
+)
+
+func TestInitialism(t *testing.T) {
+	testCases := []struct {
+		s, letters, plural string
+	}{
+		{"BBC", "BBC", ""},
+		{"BBC.", "BBC", ""},
+		{"BBCs", "BBC", "S"},
+		{"BBC's", "BBC", "'S"},
+		{"BBC’s,", "BBC", "’S"},
+		{"A", "", ""},
+		{"Bbc", "", ""},
+		{"", "", ""},
+
+		// Non-ASCII text, some of which is longer when upper-cased.
+		{"aɐ", "", ""},
+		{"ɐɐ", "", ""},
+		{"ɫx", "", ""},
+		{"ÉU", "", ""},
+		{"BBÇ", "", ""},
+	}
+	for _, tc := range testCases {
+		letters, plural := initialism(tc.s)
+		if (letters != tc.letters) || (plural != tc.plural) {
+			t.Errorf("initialism(%q): got %q, %q, want %q, %q", tc.s, letters, plural, tc.letters, tc.plural)
+		}
+	}
+
+	d, err := NewEmbeddedDictionary()
+	if err != nil {
+		t.Fatalf("NewEmbeddedDictionary: %v", err)
+	}
+	tr := NewTransliterator(d, NewDefaultAlphabet())
+	tr.TransliterateText("aɐ ɐɐ ɫx")
+}
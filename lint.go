@@ -0,0 +1,150 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// BritfoneSymbolsFilename is the filename, relative to BritfoneDir, of the
+// list of symbols that Britfone's keys and pronunciations are made of.
+const BritfoneSymbolsFilename = "britfone.symbols.3.0.1.txt"
+
+// The checks that Lint makes.
+const (
+	// LintSymbols checks that a key is made of symbols' single characters
+	// and that a pronunciation is a space-separated sequence of symbols.
+	LintSymbols = "symbols"
+
+	// LintStress checks that a pronunciation has exactly one primary
+	// stress.
+	LintStress = "stress"
+
+	// LintLetters checks that an Alphabet has a letter for every phoneme
+	// of a pronunciation.
+	LintLetters = "letters"
+)
+
+// The severities of a LintIssue.
+const (
+	// LintError is an issue that should be fixed.
+	LintError = "error"
+
+	// LintWarning is a LintStress issue with a Britfone entry. Britfone is
+	// third-party data, so such issues are reported but aren't errors.
+	LintWarning = "warning"
+)
+
+// weakForms are the words whose Britfone variants can have no primary
+// stress: weak forms, as in "A(1)" ("ə"), clitics, as in "'LL(1)" ("l"),
+// and interjections without a vowel, as in "HM" ("h m"). They aren't
+// LintStress issues.
+var weakForms = wordSet("'D 'EM 'LL 'M 'RE 'S 'VE " +
+	"A AN AND ARE AS AT BE BEEN CAN DID DOES FOR HAD HAS HAVE HER HERS HIM HIS " +
+	"IF IN INTO IS ISN'T IT ITS JUST OF OR PER SHALL THAN THAT THE THEM THIS " +
+	"TO WAS WILL WITH YOU YOUR " +
+	"HM MHM MM SH SHH UM")
+
+// LintIssue is a problem with a dictionary entry.
+type LintIssue struct {
+	// Key is the entry's key, such as "RALEIGH(2)".
+	Key string `json:"key"`
+
+	// Layer is the layer, such as LayerBritfone, that supplied the entry.
+	Layer string `json:"layer"`
+
+	// Check is the check that failed, such as LintStress.
+	Check string `json:"check"`
+
+	// Severity is LintError or LintWarning.
+	Severity string `json:"severity"`
+
+	Message string `json:"message"`
+}
+
+// LoadBritfoneSymbolsFile is like LoadBritfoneSymbols but reads from the
+// named file.
+func LoadBritfoneSymbolsFile(filename string) (map[string]bool, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBritfoneSymbols(f)
+}
+
+// LoadBritfoneSymbols returns the set of symbols listed, one per line, in a
+// Britfone symbols file.
+func LoadBritfoneSymbols(r io.Reader) (map[string]bool, error) {
+	symbols := map[string]bool{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" {
+			symbols[line] = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// Lint checks every entry of d against the symbols (as returned by
+// LoadBritfoneSymbols) and the Alphabet a. It returns the issues sorted by
+// key. A key's heteronym marker, such as the "%E" in "LEAD%E", is not
+// checked against the symbols, and the weak forms of words like "A" can have
+// no primary stress.
+func Lint(d *Dictionary, a *Alphabet, symbols map[string]bool) []LintIssue {
+	t := &Transliterator{Dictionary: d, Alphabet: a}
+	issues := []LintIssue(nil)
+	for _, k := range d.Keys() {
+		v, _ := d.Lookup(k)
+		layer, _ := d.Layer(k)
+		report := func(check string, format string, args ...interface{}) {
+			severity := LintError
+			if (check == LintStress) && (layer == LayerBritfone) {
+				severity = LintWarning
+			}
+			issues = append(issues, LintIssue{
+				Key:      k,
+				Layer:    layer,
+				Check:    check,
+				Severity: severity,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+
+		key := k
+		if i := strings.IndexByte(key, '%'); (i > 0) && (i == len(key)-2) && isAlpha(rune(key[i+1])) {
+			key = key[:i]
+		}
+		for _, r := range key {
+			if !symbols[string(r)] {
+				report(LintSymbols, "key has unknown symbol %q", r)
+				break
+			}
+		}
+
+		numStressed := 0
+		for _, p := range strings.Split(v, " ") {
+			if !symbols[p] {
+				report(LintSymbols, "pronunciation %q has unknown symbol %q", v, p)
+			}
+			numStressed += strings.Count(p, "ˈ")
+		}
+		if base, _ := splitVariant(k); (numStressed != 1) && ((numStressed != 0) || !weakForms[base]) {
+			report(LintStress, "pronunciation %q has %d primary stresses, not 1", v, numStressed)
+		}
+
+		if _, _, undrawable := t.appendLetters(nil, v); undrawable != "" {
+			report(LintLetters, "pronunciation %q has no letter for %q", v, undrawable)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+)
+
+// suffixRule derives a word ending in suffix from a base word: the word
+// without the suffix (the stem) plus replacement. If undouble is set, the
+// stem must end in a doubled consonant and one of them is dropped instead,
+// as in "DIMMED" from "DIM".
+type suffixRule struct {
+	suffix      string
+	replacement string
+	undouble    bool
+	inflect     func(base []string, baseSpelling string) []string
+}
+
+// suffixRules are tried in order. Where spelling is ambiguous, the more
+// likely base is first: "HOPED" is from "HOPE", not "HOP".
+var suffixRules = [...]suffixRule{
+	{"S", "", false, inflectS},
+	{"ES", "", false, inflectS},
+	{"IES", "Y", false, inflectS},
+
+	{"ED", "E", false, inflectED},
+	{"ED", "", false, inflectED},
+	{"ED", "", true, inflectED},
+	{"IED", "Y", false, inflectED},
+
+	{"ING", "E", false, inflectING},
+	{"ING", "", false, inflectING},
+	{"ING", "", true, inflectING},
+
+	{"ER", "E", false, inflectER},
+	{"ER", "", false, inflectER},
+	{"ER", "", true, inflectER},
+	{"IER", "Y", false, inflectER},
+
+	{"EST", "E", false, inflectEST},
+	{"EST", "", false, inflectEST},
+	{"EST", "", true, inflectEST},
+	{"IEST", "Y", false, inflectEST},
+
+	{"ICALLY", "IC", false, inflectALLY},
+	{"ILY", "Y", false, inflectILY},
+	{"LY", "LE", false, inflectBLY},
+	{"LY", "", false, inflectLY},
+}
+
+// prefixRules' prefixes have no primary stress: "UN" is unstressed and "RE"
+// has a secondary stress, as in "REDO". The base word keeps its stress.
+var prefixRules = [...]struct {
+	prefix   string
+	phonemes []string
+}{
+	{"UN", []string{"ɐ", "n"}},
+	{"RE", []string{"ɹ", "ˌiː"}},
+}
+
+// maxDeriveDepth is how many suffixes and prefixes can be stacked, as in
+// "UNFINISHED" from "FINISHED" from "FINISH".
+const maxDeriveDepth = 2
+
+// derive returns the pronunciation of an upper-case word that is not in the
+// dictionary but is an inflection or derivation of one that is, such as
+// "DIMMED" from "DIM", "UNFAIR" from "FAIR" or, as per contract, "LADY'S"
+// from "LADY".
+func (t *Transliterator) derive(k string, depth int) (string, bool) {
+	if (depth <= 0) || !isG2PWord(k) {
+		return "", false
+	}
+	if v, ok := t.contract(k, depth); ok {
+		return v, true
+	}
+
+	for _, r := range suffixRules {
+		if !strings.HasSuffix(k, r.suffix) {
+			continue
+		}
+		stem := k[:len(k)-len(r.suffix)]
+		if len(stem) < 2 {
+			continue
+		}
+		base := stem + r.replacement
+		if r.undouble {
+			n := len(stem)
+			if (stem[n-1] != stem[n-2]) || !isConsonantLetter(stem[n-1]) {
+				continue
+			}
+			base = stem[:n-1]
+		} else if (r.replacement == "E") && (r.suffix == "ING") && strings.HasSuffix(stem, "NG") {
+			// "SINGING" is from "SING", not "SINGE".
+			continue
+		}
+		if v, ok := t.lookupBase(base, depth); ok {
+			return strings.Join(r.inflect(strings.Fields(v), base), " "), true
+		}
+	}
+
+	for _, r := range prefixRules {
+		base := strings.TrimPrefix(k, r.prefix)
+		if (len(base) == len(k)) || (len(base) < 3) {
+			continue
+		}
+		if v, ok := t.lookupBase(base, depth); ok {
+			return strings.Join(r.phonemes, " ") + " " + v, true
+		}
+	}
+	return "", false
+}
+
+// lookupBase looks up a base word in the dictionary, without logging, or
+// derives it from a further base word.
+func (t *Transliterator) lookupBase(k string, depth int) (string, bool) {
+	if v, ok := t.Dictionary.Lookup(k); ok {
+		return v, true
+	} else if n := t.Dictionary.pickVariant(k, t.VariantPolicy); n > 0 {
+		return t.Dictionary.Variant(k, n)
+	}
+	return t.derive(k, depth-1)
+}
+
+func isConsonantLetter(c byte) bool {
+	return ('A' <= c) && (c <= 'Z') && !strings.ContainsRune("AEIOU", rune(c))
+}
+
+func lastPhoneme(phonemes []string) string {
+	if len(phonemes) == 0 {
+		return ""
+	}
+	return stripStress(phonemes[len(phonemes)-1])
+}
+
+func isSibilant(p string) bool {
+	switch p {
+	case "s", "z", "ʃ", "ʒ", "tʃ", "dʒ":
+		return true
+	}
+	return false
+}
+
+func isVoiceless(p string) bool {
+	switch p {
+	case "p", "t", "k", "f", "θ", "s", "ʃ", "tʃ":
+		return true
+	}
+	return false
+}
+
+// linkingR adds the "r" of a base word like "DISAPPEAR", silent in
+// non-rhotic accents, before a vowel-initial suffix.
+func linkingR(base []string, baseSpelling string) []string {
+	if (strings.HasSuffix(baseSpelling, "R") || strings.HasSuffix(baseSpelling, "RE")) &&
+		isVowelPhoneme(lastPhoneme(base)) {
+		return append(base, "ɹ")
+	}
+	return base
+}
+
+// inflectS adds a plural, possessive or third person "s": /ɪz/ after a
+// sibilant, /s/ after any other voiceless consonant, or else /z/.
+func inflectS(base []string, baseSpelling string) []string {
+	switch p := lastPhoneme(base); {
+	case isSibilant(p):
+		return append(base, "ɪ", "z")
+	case isVoiceless(p):
+		return append(base, "s")
+	}
+	return append(base, "z")
+}
+
+// inflectED adds a past tense "ed": /ɪd/ after /t/ or /d/, /t/ after any
+// other voiceless consonant, or else /d/.
+func inflectED(base []string, baseSpelling string) []string {
+	switch p := lastPhoneme(base); {
+	case (p == "t") || (p == "d"):
+		return append(base, "ɪ", "d")
+	case isVoiceless(p):
+		return append(base, "t")
+	}
+	return append(base, "d")
+}
+
+func inflectING(base []string, baseSpelling string) []string {
+	return append(linkingR(base, baseSpelling), "ɪ", "ŋ")
+}
+
+func inflectER(base []string, baseSpelling string) []string {
+	return append(linkingR(base, baseSpelling), "ə")
+}
+
+func inflectEST(base []string, baseSpelling string) []string {
+	return append(linkingR(base, baseSpelling), "ɪ", "s", "t")
+}
+
+func inflectALLY(base []string, baseSpelling string) []string {
+	return append(base, "ə", "l", "i")
+}
+
+// inflectILY turns "HAPPY" /ˈhæpi/ into "HAPPILY" /ˈhæpɪli/.
+func inflectILY(base []string, baseSpelling string) []string {
+	if n := len(base); (n > 0) && (base[n-1] == "i") {
+		base = append(base[:n-1], "ɪ")
+	}
+	return append(base, "l", "i")
+}
+
+// inflectBLY turns "NOBLE" /ˈnəʊbəl/ into "NOBLY" /ˈnəʊbli/.
+func inflectBLY(base []string, baseSpelling string) []string {
+	if n := len(base); (n > 1) && (base[n-2] == "ə") && (base[n-1] == "l") {
+		return append(base[:n-2], "l", "i")
+	}
+	return append(base, "l", "i")
+}
+
+// inflectLY turns "REAL" /ˈɹɪəl/ into "REALLY" /ˈɹɪəli/, without doubling
+// the /l/.
+func inflectLY(base []string, baseSpelling string) []string {
+	if lastPhoneme(base) == "l" {
+		return append(base, "i")
+	}
+	return append(base, "l", "i")
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	d := NewDictionary()
+	for k, v := range map[string]string{
+		"'S(1)":     "z",
+		"'S(2)":     "s",
+		"BASIC":     "b ˈeɪ s ɪ k",
+		"CAT":       "k ˈæ t",
+		"DIM":       "d ˈɪ m",
+		"DISAPPEAR": "d ˌɪ s ə p ˈɪə",
+		"DO":        "d ˈuː",
+		"FAIR":      "f ˈɛə",
+		"FINISH":    "f ˈɪ n ɪ ʃ",
+		"GENTLE":    "dʒ ˈɛ n t ə l",
+		"HAPPY":     "h ˈæ p i",
+		"HOP":       "h ˈɒ p",
+		"HOPE":      "h ˈəʊ p",
+		"LADY":      "l ˈeɪ d i",
+		"QUICK":     "k w ˈɪ k",
+		"SING":      "s ˈɪ ŋ",
+		"WANT":      "w ˈɒ n t",
+		"WATCH":     "w ˈɒ tʃ",
+	} {
+		if err := d.Add(k, v, LayerUser); err != nil {
+			t.Fatalf("Add(%q, %q): %v", k, v, err)
+		}
+	}
+	tr := &Transliterator{Dictionary: d, Derive: true}
+
+	testCases := []struct {
+		k    string
+		want string
+	}{
+		// Suffixes.
+		{"CATS", "k ˈæ t s"},
+		{"WATCHES", "w ˈɒ tʃ ɪ z"},
+		{"DIMMED", "d ˈɪ m d"},
+		{"HOPED", "h ˈəʊ p t"},
+		{"WANTED", "w ˈɒ n t ɪ d"},
+		{"HOPPING", "h ˈɒ p ɪ ŋ"},
+		{"SINGING", "s ˈɪ ŋ ɪ ŋ"},
+		{"DISAPPEARING", "d ˌɪ s ə p ˈɪə ɹ ɪ ŋ"},
+		{"HAPPIER", "h ˈæ p i ə"},
+		{"QUICKEST", "k w ˈɪ k ɪ s t"},
+		{"HAPPILY", "h ˈæ p ɪ l i"},
+		{"GENTLY", "dʒ ˈɛ n t l i"},
+		{"QUICKLY", "k w ˈɪ k l i"},
+		{"BASICALLY", "b ˈeɪ s ɪ k ə l i"},
+
+		// Prefixes, which can stack with a suffix.
+		{"UNFAIR", "ɐ n f ˈɛə"},
+		{"UNFINISHED", "ɐ n f ˈɪ n ɪ ʃ t"},
+		{"REFINISHED", "ɹ ˌiː f ˈɪ n ɪ ʃ t"},
+
+		// Clitics.
+		{"LADY'S", "l ˈeɪ d i z"},
+		{"CAT'S", "k ˈæ t s"},
+
+		// Too short a base, too many affixes or no base at all.
+		{"REDO", ""},
+		{"UNREFINISHED", ""},
+		{"XYZZY", ""},
+	}
+	for _, tc := range testCases {
+		got, ok := tr.derive(tc.k, maxDeriveDepth)
+		if (got != tc.want) || (ok != (tc.want != "")) {
+			t.Errorf("derive(%q): got %q, %t, want %q", tc.k, got, ok, tc.want)
+		}
+	}
+}
@@ -0,0 +1,671 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strconv"
+	"strings"
+)
+
+// readNumeral reads the numeral, such as "12", "3rd", "1,000", "2.5km",
+// "£5.50", "10:30pm", "1984", "12/05/2020" or "3 May 2021", at the start of
+// s. It returns the dictionary keys, as in an expansion, that the numeral is
+// read as, and the numeral's length in bytes. The length is zero if s doesn't
+// start with a numeral.
+//
+// A unit or time suffix can also be a separate word, as in "5 km" or "3
+// p.m.". Numeric dates are read day first, as in British English. A date can
+// also start with a month name, as in "May 3rd" or "May 3, 2021".
+func readNumeral(s string) (keys []string, n int) {
+	if m, j := scanMonth(s, 0); m > 0 {
+		return readMonthDay(s, m, j)
+	}
+
+	i := 0
+	if strings.HasPrefix(s, "-") {
+		keys, i = append(keys, "MINUS"), 1
+	}
+	cur, hasCur := currency{}, false
+	for _, c := range currencies {
+		if strings.HasPrefix(s[i:], c.symbol) {
+			cur, hasCur, i = c, true, i+len(c.symbol)
+			break
+		}
+	}
+
+	// A sign or currency symbol can be followed by a bare decimal point, as
+	// in "-.5".
+	whole, grouped, i := scanDigits(s, i)
+	if (whole == "") && ((i == 0) || (i+1 >= len(s)) || (s[i] != '.') || !isDigit(s[i+1])) {
+		return nil, 0
+	}
+	fraction := ""
+	if (i+1 < len(s)) && (s[i] == '.') && isDigit(s[i+1]) {
+		j := i + 1
+		for ; (j < len(s)) && isDigit(s[j]); j++ {
+		}
+		fraction, i = s[i+1:j], j
+	}
+	plain := !hasCur && !grouped && (fraction == "")
+
+	if hasCur {
+		if m, j := scanWord(s, i, multipliers); m != "" {
+			keys = append(append(keys, numberWords(whole, fraction)...), m, cur.many)
+			return keys, j
+		}
+		keys = append(keys, currencyWords(cur, whole, fraction)...)
+		return keys, i
+	}
+
+	if plain && (len(whole) <= 2) {
+		if k, j := readTime(s, whole, i); j > 0 {
+			return append(keys, k...), j
+		} else if k, j := readDayMonthYear(s, whole, i); j > 0 {
+			return append(keys, k...), j
+		} else if k, j := readDayMonthName(s, whole, i); j > 0 {
+			return append(keys, k...), j
+		}
+	}
+	if plain && (len(whole) == 4) {
+		if k, j := readISODate(s, whole, i); j > 0 {
+			return append(keys, k...), j
+		}
+	}
+
+	if plain {
+		for _, suffix := range [...]string{"st", "nd", "rd", "th"} {
+			if j := i + len(suffix); hasSuffixAt(s, i, j, suffix) {
+				return append(keys, ordinalWords(cardinalWords(whole))...), j
+			}
+		}
+		if (i < len(s)) && (s[i] == 'p') && endsWord(s, i+1) {
+			keys = append(keys, cardinalWords(whole)...)
+			if whole == "1" {
+				return append(keys, currencies[0].subOne), i + 1
+			}
+			return append(keys, currencies[0].subMany), i + 1
+		}
+	}
+	if (i < len(s)) && (s[i] == '%') {
+		return append(append(keys, numberWords(whole, fraction)...), "PERCENT"), i + 1
+	}
+
+	j := skipBlanks(s, i)
+	if u, k := scanUnit(s, j); u != (unit{}) {
+		keys = append(keys, numberWords(whole, fraction)...)
+		if (whole == "1") && (fraction == "") {
+			return append(keys, u.one), k
+		}
+		return append(keys, u.many), k
+	}
+
+	if y, _ := strconv.Atoi(whole); plain && (len(whole) == 4) && (1000 <= y) && (y < 2100) {
+		if hasSuffixAt(s, i, i+1, "s") {
+			if k := decadeWords(y); k != nil {
+				return append(keys, k...), i + 1
+			}
+		} else if endsWord(s, i) {
+			return append(keys, yearWords(y)...), i
+		}
+	}
+	return append(keys, numberWords(whole, fraction)...), i
+}
+
+// isNumeral returns whether s starts with a numeral, as per readNumeral.
+func isNumeral(s string) bool {
+	_, n := readNumeral(s)
+	return n > 0
+}
+
+type currency struct {
+	symbol    string
+	one, many string
+
+	// subOne and subMany name a hundredth of the currency.
+	subOne, subMany string
+}
+
+// currencies' first element is also the currency of amounts like "50p".
+var currencies = [...]currency{
+	{"£", "POUND", "POUNDS", "PENNY", "PENCE"},
+	{"$", "DOLLAR", "DOLLARS", "CENT", "CENTS"},
+	{"€", "EURO", "EUROS", "CENT", "CENTS"},
+}
+
+// multipliers are what amounts of money can be suffixed with, as in "£5m".
+var multipliers = map[string]string{
+	"k":  "THOUSAND",
+	"m":  "MILLION",
+	"bn": "BILLION",
+}
+
+type unit struct {
+	one, many string
+}
+
+// units are keyed by their abbreviation, which is case-sensitive.
+var units = map[string]unit{
+	"°":    {"DEGREE", "DEGREES"},
+	"°C":   {"DEGREE CELSIUS", "DEGREES CELSIUS"},
+	"°F":   {"DEGREE FAHRENHEIT", "DEGREES FAHRENHEIT"},
+	"cm":   {"CENTIMETRE", "CENTIMETRES"},
+	"ft":   {"FOOT", "FEET"},
+	"g":    {"GRAM", "GRAMS"},
+	"h":    {"HOUR", "HOURS"},
+	"hr":   {"HOUR", "HOURS"},
+	"hrs":  {"HOURS", "HOURS"},
+	"kg":   {"KILOGRAM", "KILOGRAMS"},
+	"km":   {"KILOMETRE(1)", "KILOMETRES(1)"},
+	"km/h": {"KILOMETRE(1) PER(1) HOUR", "KILOMETRES(1) PER(1) HOUR"},
+	"kph":  {"KILOMETRE(1) PER(1) HOUR", "KILOMETRES(1) PER(1) HOUR"},
+	"l":    {"LITRE", "LITRES"},
+	"L":    {"LITRE", "LITRES"},
+	"lb":   {"POUND", "POUNDS"},
+	"lbs":  {"POUNDS", "POUNDS"},
+	"m":    {"METRE", "METRES"},
+	"mg":   {"MILLIGRAM", "MILLIGRAMS"},
+	"mi":   {"MILE", "MILES"},
+	"min":  {"MINUTE(1)", "MINUTES"},
+	"mins": {"MINUTES", "MINUTES"},
+	"ml":   {"MILLILITRE", "MILLILITRES"},
+	"mL":   {"MILLILITRE", "MILLILITRES"},
+	"mm":   {"MILLIMETRE", "MILLIMETRES"},
+	"mph":  {"MILE PER(1) HOUR", "MILES PER(1) HOUR"},
+	"oz":   {"OUNCE", "OUNCES"},
+	"sec":  {"SECOND(1)", "SECONDS(1)"},
+	"secs": {"SECONDS(1)", "SECONDS(1)"},
+}
+
+var months = [...]string{
+	"JANUARY", "FEBRUARY(1)", "MARCH", "APRIL", "MAY", "JUNE",
+	"JULY(1)", "AUGUST", "SEPTEMBER", "OCTOBER", "NOVEMBER", "DECEMBER",
+}
+
+// monthNames maps the names of months, and their abbreviations, to the
+// months' numbers. They are capitalized, so that "may" isn't a month.
+var monthNames = map[string]int{
+	"January": 1, "February": 2, "March": 3, "April": 4, "May": 5, "June": 6,
+	"July": 7, "August": 8, "September": 9, "October": 10, "November": 11, "December": 12,
+
+	"Jan": 1, "Feb": 2, "Mar": 3, "Apr": 4, "Jun": 6, "Jul": 7,
+	"Aug": 8, "Sep": 9, "Sept": 9, "Oct": 10, "Nov": 11, "Dec": 12,
+}
+
+// timeSuffixes are what a time can end with, as in "10am" or "10 a.m.". The
+// final full stop of "a.m." is left as punctuation, as it often also ends a
+// sentence.
+var timeSuffixes = [...]struct {
+	abbrevs []string
+	keys    []string
+}{
+	{[]string{"am", "AM", "a.m", "A.M"}, []string{"A(2)", "EM"}},
+	{[]string{"pm", "PM", "p.m", "P.M"}, []string{"PEE", "EM"}},
+}
+
+var cardinals = [...]string{
+	"ZERO", "ONE", "TWO", "THREE", "FOUR", "FIVE", "SIX", "SEVEN", "EIGHT", "NINE",
+	"TEN", "ELEVEN", "TWELVE", "THIRTEEN(2)", "FOURTEEN(2)", "FIFTEEN(2)",
+	"SIXTEEN(2)", "SEVENTEEN(2)", "EIGHTEEN(2)", "NINETEEN(2)",
+}
+
+var tens = [...]string{
+	"", "", "TWENTY", "THIRTY", "FORTY", "FIFTY", "SIXTY", "SEVENTY", "EIGHTY", "NINETY",
+}
+
+var scales = [...]struct {
+	value uint64
+	key   string
+}{
+	{1000000000, "BILLION"},
+	{1000000, "MILLION"},
+	{1000, "THOUSAND"},
+}
+
+// ordinals maps the last word of a cardinal to that of the ordinal.
+var ordinals = map[string]string{
+	"ZERO":         "ZEROTH",
+	"ONE":          "FIRST",
+	"TWO":          "SECOND(1)",
+	"THREE":        "THIRD",
+	"FOUR":         "FOURTH",
+	"FIVE":         "FIFTH",
+	"SIX":          "SIXTH",
+	"SEVEN":        "SEVENTH",
+	"EIGHT":        "EIGHTH",
+	"NINE":         "NINTH",
+	"TEN":          "TENTH",
+	"ELEVEN":       "ELEVENTH",
+	"TWELVE":       "TWELFTH",
+	"THIRTEEN(2)":  "THIRTEENTH",
+	"FOURTEEN(2)":  "FOURTEENTH(2)",
+	"FIFTEEN(2)":   "FIFTEENTH",
+	"SIXTEEN(2)":   "SIXTEENTH(1)",
+	"SEVENTEEN(2)": "SEVENTEENTH",
+	"EIGHTEEN(2)":  "EIGHTEENTH(2)",
+	"NINETEEN(2)":  "NINETEENTH",
+	"TWENTY":       "TWENTIETH",
+	"THIRTY":       "THIRTIETH",
+	"FORTY":        "FORTIETH",
+	"FIFTY":        "FIFTIETH",
+	"SIXTY":        "SIXTIETH",
+	"SEVENTY":      "SEVENTIETH",
+	"EIGHTY":       "EIGHTIETH",
+	"NINETY":       "NINETIETH",
+	"HUNDRED(1)":   "HUNDREDTH",
+	"THOUSAND":     "THOUSANDTH",
+	"MILLION":      "MILLIONTH",
+	"BILLION":      "BILLIONTH",
+}
+
+// cardinalWords reads digits as a number, in British English: "ONE HUNDRED
+// AND FIVE". Numbers with a leading zero, or too large to name, are read
+// digit by digit.
+func cardinalWords(digits string) []string {
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if (err != nil) || (n >= 1000*scales[0].value) || ((len(digits) > 1) && (digits[0] == '0')) {
+		return digitWords(digits)
+	} else if n == 0 {
+		return []string{cardinals[0]}
+	}
+	keys := []string(nil)
+	for _, s := range scales {
+		if n >= s.value {
+			keys = append(append(keys, smallCardinalWords(n/s.value)...), s.key)
+			n %= s.value
+		}
+	}
+	if (n > 0) && (n < 100) && (len(keys) > 0) {
+		keys = append(keys, "AND")
+	}
+	return append(keys, smallCardinalWords(n)...)
+}
+
+// smallCardinalWords reads a number less than 1000.
+func smallCardinalWords(n uint64) []string {
+	keys := []string(nil)
+	if n >= 100 {
+		keys = append(keys, cardinals[n/100], "HUNDRED(1)")
+		if n %= 100; n > 0 {
+			keys = append(keys, "AND")
+		}
+	}
+	if n >= 20 {
+		keys = append(keys, tens[n/10])
+		n %= 10
+	}
+	if n > 0 {
+		keys = append(keys, cardinals[n])
+	}
+	return keys
+}
+
+func digitWords(digits string) []string {
+	keys := make([]string, len(digits))
+	for i := range digits {
+		keys[i] = cardinals[digits[i]-'0']
+	}
+	return keys
+}
+
+// numberWords reads a whole number and its decimal fraction, if any.
+func numberWords(whole string, fraction string) []string {
+	keys := cardinalWords(whole)
+	if fraction != "" {
+		keys = append(append(keys, "POINT"), digitWords(fraction)...)
+	}
+	return keys
+}
+
+// ordinalWords turns a cardinal's keys into the ordinal's.
+func ordinalWords(keys []string) []string {
+	if o, ok := ordinals[keys[len(keys)-1]]; ok {
+		keys[len(keys)-1] = o
+	}
+	return keys
+}
+
+// yearWords reads a year in pairs of digits, as in "NINETEEN OH FIVE",
+// except for years like 2005 that are read as a number.
+func yearWords(y int) []string {
+	hi, lo := strconv.Itoa(y/100), y%100
+	switch {
+	case (y%1000 == 0) || ((2000 <= y) && (y < 2010)):
+		return cardinalWords(strconv.Itoa(y))
+	case lo == 0:
+		return append(cardinalWords(hi), "HUNDRED(1)")
+	case lo < 10:
+		return append(cardinalWords(hi), "OH", cardinals[lo])
+	}
+	return append(cardinalWords(hi), cardinalWords(strconv.Itoa(lo))...)
+}
+
+// decades maps the last word of a year ending in zero to its decade.
+var decades = map[string]string{
+	"TEN":        "TENS",
+	"TWENTY":     "TWENTIES",
+	"THIRTY":     "THIRTIES",
+	"FORTY":      "FORTIES",
+	"FIFTY":      "FIFTIES",
+	"SIXTY":      "SIXTIES",
+	"SEVENTY":    "SEVENTIES",
+	"EIGHTY":     "EIGHTIES",
+	"NINETY":     "NINETIES",
+	"HUNDRED(1)": "HUNDREDS",
+	"THOUSAND":   "THOUSANDS",
+}
+
+// decadeWords reads a decade or century, as in "1980s", "1900s" or "2000s",
+// or returns nil.
+func decadeWords(y int) []string {
+	if y%10 != 0 {
+		return nil
+	}
+	keys := yearWords(y)
+	d, ok := decades[keys[len(keys)-1]]
+	if !ok {
+		return nil
+	}
+	keys[len(keys)-1] = d
+	return keys
+}
+
+// currencyWords reads an amount of money, such as "FIVE POUNDS AND FIFTY
+// PENCE". A fraction that isn't a whole number of cents is read as a
+// decimal.
+func currencyWords(c currency, whole string, fraction string) []string {
+	if len(fraction) > 2 {
+		return append(numberWords(whole, fraction), c.many)
+	}
+	fraction = padCents(fraction)
+	keys := []string(nil)
+	if w, _ := strconv.Atoi(whole); (w != 0) || (fraction == "00") {
+		keys = append(keys, cardinalWords(whole)...)
+		if whole == "1" {
+			keys = append(keys, c.one)
+		} else {
+			keys = append(keys, c.many)
+		}
+		if fraction == "00" {
+			return keys
+		}
+		keys = append(keys, "AND")
+	}
+	keys = append(keys, cardinalWords(strings.TrimPrefix(fraction, "0"))...)
+	if fraction == "01" {
+		return append(keys, c.subOne)
+	}
+	return append(keys, c.subMany)
+}
+
+// padCents pads a decimal fraction, such as "5" in "£2.5", to two digits.
+func padCents(fraction string) string {
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	return fraction
+}
+
+// readTime reads the rest of a time like "10:30", "10:30pm" or "10am", given
+// its hour digits and their end. It returns zero if there is no time.
+func readTime(s string, hour string, i int) (keys []string, n int) {
+	h, _ := strconv.Atoi(hour)
+	minutes := ""
+	if (i+2 < len(s)) && (s[i] == ':') && isDigit(s[i+1]) && isDigit(s[i+2]) && (s[i+1] <= '5') {
+		minutes, i = s[i+1:i+3], i+3
+	}
+	suffix := []string(nil)
+	if (1 <= h) && (h <= 12) {
+		j := skipBlanks(s, i)
+	loop:
+		for _, x := range timeSuffixes {
+			for _, abbrev := range x.abbrevs {
+				k := j + len(abbrev)
+				if (abbrev[1] == '.') && ((k >= len(s)) || (s[k] != '.')) {
+					continue
+				} else if hasSuffixAt(s, j, k, abbrev) {
+					suffix, i = x.keys, k
+					break loop
+				}
+			}
+		}
+	}
+	if ((minutes == "") && (suffix == nil)) || (h > 23) || !endsWord(s, i) {
+		return nil, 0
+	}
+
+	keys = cardinalWords(strconv.Itoa(h))
+	switch {
+	case (minutes == "00") && (suffix == nil):
+		keys = append(keys, "O'CLOCK")
+	case (minutes == "") || (minutes == "00"):
+		// No-op.
+	case minutes[0] == '0':
+		keys = append(keys, "OH", cardinals[minutes[1]-'0'])
+	default:
+		keys = append(keys, cardinalWords(minutes)...)
+	}
+	return append(keys, suffix...), i
+}
+
+// readDayMonthYear reads the rest of a date like "12/05/2020" or "12/5/20",
+// given its day digits and their end. It returns zero if there is no date.
+func readDayMonthYear(s string, day string, i int) (keys []string, n int) {
+	if (i >= len(s)) || (s[i] != '/') {
+		return nil, 0
+	}
+	month, _, j := scanDigits(s, i+1)
+	if (len(month) > 2) || (j >= len(s)) || (s[j] != '/') {
+		return nil, 0
+	}
+	year, _, k := scanDigits(s, j+1)
+	if ((len(year) != 2) && (len(year) != 4)) || !endsWord(s, k) {
+		return nil, 0
+	}
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	if keys = dateWords(day, month, year); keys == nil {
+		return nil, 0
+	}
+	return keys, k
+}
+
+// readDayMonthName reads the rest of a date like "3 May", "3rd May 2021" or
+// "3 Jan. 2021", given its day digits and their end. It returns zero if
+// there is no date.
+func readDayMonthName(s string, day string, i int) (keys []string, n int) {
+	for _, suffix := range [...]string{"st", "nd", "rd", "th"} {
+		if hasSuffixAt(s, i, i+len(suffix), suffix) {
+			i += len(suffix)
+			break
+		}
+	}
+	j := skipBlanks(s, i)
+	if j == i {
+		return nil, 0
+	}
+	m, k := scanMonth(s, j)
+	if m == 0 {
+		return nil, 0
+	}
+	y, l := scanYear(s, k)
+	if l > 0 {
+		k = l
+	}
+	if keys = dateWords(day, strconv.Itoa(m), y); keys == nil {
+		return nil, 0
+	}
+	return keys, k
+}
+
+// readMonthDay reads the rest of a date like "May 3rd", "May 3, 2021" or
+// "Jan. 3rd", given its month and the month name's end. As "In May 3 people
+// left" isn't a date, the day needs an ordinal suffix or a year after it. It
+// returns zero if there is no date.
+func readMonthDay(s string, month int, i int) (keys []string, n int) {
+	if (i < len(s)) && (s[i] == '.') {
+		i++
+	}
+	j := skipBlanks(s, i)
+	if j == i {
+		return nil, 0
+	}
+	day, grouped, k := scanDigits(s, j)
+	if (day == "") || (len(day) > 2) || grouped {
+		return nil, 0
+	}
+	ordinal := false
+	for _, suffix := range [...]string{"st", "nd", "rd", "th"} {
+		if hasSuffixAt(s, k, k+len(suffix), suffix) {
+			ordinal, k = true, k+len(suffix)
+			break
+		}
+	}
+	if !endsWord(s, k) {
+		return nil, 0
+	}
+	y, l := scanYear(s, k)
+	if !ordinal && (l == 0) {
+		return nil, 0
+	} else if l > 0 {
+		k = l
+	}
+
+	// Read "May the third", not "the third of May".
+	if d, _ := strconv.Atoi(day); (d < 1) || (31 < d) {
+		return nil, 0
+	}
+	keys = append([]string{months[month-1], "THE"}, ordinalWords(cardinalWords(day))...)
+	return append(keys, yearKeys(y)...), k
+}
+
+// scanMonth returns the month named at s[i], in title or upper case, and
+// where the name ends. It returns zero if there is no month name.
+func scanMonth(s string, i int) (month int, end int) {
+	for name, m := range monthNames {
+		for _, v := range [...]string{name, strings.ToUpper(name)} {
+			if j := i + len(v); (j > end) && hasSuffixAt(s, i, j, v) {
+				month, end = m, j
+			}
+		}
+	}
+	return month, end
+}
+
+// scanYear returns the year, such as "2021", after s[i] and blanks, or after
+// a full stop or comma and blanks, and where it ends. It returns zero if
+// there is no year.
+func scanYear(s string, i int) (year string, end int) {
+	if (i < len(s)) && ((s[i] == '.') || (s[i] == ',')) {
+		i++
+	}
+	j := skipBlanks(s, i)
+	if j == i {
+		return "", 0
+	}
+	digits, grouped, k := scanDigits(s, j)
+	if y, _ := strconv.Atoi(digits); grouped || (len(digits) != 4) || (y < 1000) || (2100 <= y) || !endsWord(s, k) {
+		return "", 0
+	}
+	return digits, k
+}
+
+// readISODate reads the rest of a date like "2020-05-12", given its year
+// digits and their end. It returns zero if there is no date.
+func readISODate(s string, year string, i int) (keys []string, n int) {
+	if (i+6 > len(s)) || (s[i] != '-') || (s[i+3] != '-') || !endsWord(s, i+6) {
+		return nil, 0
+	}
+	month, day := s[i+1:i+3], s[i+4:i+6]
+	if !isDigit(month[0]) || !isDigit(month[1]) || !isDigit(day[0]) || !isDigit(day[1]) {
+		return nil, 0
+	}
+	if keys = dateWords(day, month, year); keys == nil {
+		return nil, 0
+	}
+	return keys, i + 6
+}
+
+// dateWords reads a date as "THE TWELFTH OF MAY TWENTY TWENTY". The year
+// can be empty. It returns nil if the day or month is out of range.
+func dateWords(day string, month string, year string) []string {
+	d, _ := strconv.Atoi(day)
+	m, _ := strconv.Atoi(month)
+	if (d < 1) || (31 < d) || (m < 1) || (12 < m) {
+		return nil
+	}
+	keys := append([]string{"THE"}, ordinalWords(cardinalWords(strconv.Itoa(d)))...)
+	return append(append(keys, "OF", months[m-1]), yearKeys(year)...)
+}
+
+// yearKeys reads a year, as per yearWords, or returns nil for an empty year.
+func yearKeys(year string) []string {
+	if year == "" {
+		return nil
+	}
+	y, _ := strconv.Atoi(year)
+	return yearWords(y)
+}
+
+// scanDigits returns the digits starting at s[i], without any thousands
+// separators, whether there were any separators and where the digits end.
+func scanDigits(s string, i int) (digits string, grouped bool, end int) {
+	j := i
+	for ; (j < len(s)) && isDigit(s[j]); j++ {
+	}
+	if (j == i) || (j-i > 3) {
+		return s[i:j], false, j
+	}
+	sb := strings.Builder{}
+	sb.WriteString(s[i:j])
+	for (j+3 < len(s)) && (s[j] == ',') && isDigit(s[j+1]) && isDigit(s[j+2]) && isDigit(s[j+3]) &&
+		((j+4 == len(s)) || !isDigit(s[j+4])) {
+		sb.WriteString(s[j+1 : j+4])
+		grouped, j = true, j+4
+	}
+	return sb.String(), grouped, j
+}
+
+// scanWord returns words[w] for the longest w that starts at s[i] and ends
+// a word, and where w ends.
+func scanWord(s string, i int, words map[string]string) (word string, end int) {
+	for k, v := range words {
+		if j := i + len(k); (j > end) && hasSuffixAt(s, i, j, k) {
+			word, end = v, j
+		}
+	}
+	return word, end
+}
+
+// scanUnit is like scanWord, but for units.
+func scanUnit(s string, i int) (u unit, end int) {
+	for k, v := range units {
+		if j := i + len(k); (j > end) && hasSuffixAt(s, i, j, k) {
+			u, end = v, j
+		}
+	}
+	return u, end
+}
+
+// skipBlanks returns where the spaces and tabs starting at s[i] end.
+func skipBlanks(s string, i int) int {
+	for ; (i < len(s)) && ((s[i] == ' ') || (s[i] == '\t')); i++ {
+	}
+	return i
+}
+
+// hasSuffixAt returns whether s[i:j] is x and ends a word.
+func hasSuffixAt(s string, i int, j int, x string) bool {
+	return (j <= len(s)) && (s[i:j] == x) && endsWord(s, j)
+}
+
+// endsWord returns whether s[i] does not continue a word or a number.
+func endsWord(s string, i int) bool {
+	return (i >= len(s)) || !(isAlpha(rune(s[i])) || isDigit(s[i]))
+}
+
+func isDigit(c byte) bool {
+	return ('0' <= c) && (c <= '9')
+}
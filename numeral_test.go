@@ -0,0 +1,78 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadNumeral(t *testing.T) {
+	testCases := []struct {
+		s    string
+		want string
+		n    int
+	}{
+		{"12", "TWELVE", 2},
+		{"0", "ZERO", 1},
+		{"-7", "MINUS SEVEN", 2},
+		{"-.5", "MINUS POINT FIVE", 3},
+		{"-.5%", "MINUS POINT FIVE PERCENT", 4},
+		{"-.", "", 0},
+		{".5", "", 0},
+		{"100", "ONE HUNDRED(1)", 3},
+		{"1,000", "ONE THOUSAND", 5},
+		{"1,234,567", "ONE MILLION TWO HUNDRED(1) AND THIRTY FOUR THOUSAND FIVE HUNDRED(1) AND SIXTY SEVEN", 9},
+		{"3rd", "THIRD", 3},
+		{"21st", "TWENTY FIRST", 4},
+		{"0th", "ZEROTH", 3},
+		{"100th", "ONE HUNDREDTH", 5},
+		{"50%", "FIFTY PERCENT", 3},
+
+		// Currencies and units.
+		{"£5.50", "FIVE POUNDS AND FIFTY PENCE", 6},
+		{"$3", "THREE DOLLARS", 2},
+		{"20p", "TWENTY PENCE", 3},
+		{"$.50", "FIFTY CENTS", 4},
+		{"2.5km", "TWO POINT FIVE KILOMETRES(1)", 5},
+		{"5 km", "FIVE KILOMETRES(1)", 4},
+
+		// Times. The final full stop of "a.m." is left as punctuation.
+		{"7:05", "SEVEN OH FIVE", 4},
+		{"10:30pm", "TEN THIRTY PEE EM", 7},
+		{"3pm", "THREE PEE EM", 3},
+		{"3 pm", "THREE PEE EM", 4},
+		{"3 PM", "THREE PEE EM", 4},
+		{"3 p.m.", "THREE PEE EM", 5},
+		{"10:30 a.m. today", "TEN THIRTY A(2) EM", 9},
+		{"25:00", "TWENTY FIVE", 2},
+
+		// Years and dates.
+		{"1984", "NINETEEN(2) EIGHTY FOUR", 4},
+		{"1990s", "NINETEEN(2) NINETIES", 5},
+		{"1900s", "NINETEEN(2) HUNDREDS", 5},
+		{"2000s", "TWO THOUSANDS", 5},
+		{"2010s", "TWENTY TENS", 5},
+		{"2005", "TWO THOUSAND AND FIVE", 4},
+		{"12/05/2020", "THE TWELFTH OF MAY TWENTY TWENTY", 10},
+		{"2020-05-12", "THE TWELFTH OF MAY TWENTY TWENTY", 10},
+		{"3 May 2021", "THE THIRD OF MAY TWENTY TWENTY ONE", 10},
+		{"3rd May", "THE THIRD OF MAY", 7},
+		{"3 Jan. 2021", "THE THIRD OF JANUARY TWENTY TWENTY ONE", 11},
+		{"May 3, 2021", "MAY THE THIRD TWENTY TWENTY ONE", 11},
+		{"May 3rd", "MAY THE THIRD", 7},
+		{"May 3 people", "", 0},
+		{"3 may", "THREE", 1},
+
+		{"", "", 0},
+		{"abc", "", 0},
+	}
+	for _, tc := range testCases {
+		keys, n := readNumeral(tc.s)
+		if got := strings.Join(keys, " "); (got != tc.want) || (n != tc.n) {
+			t.Errorf("readNumeral(%q): got %q, %d, want %q, %d", tc.s, got, n, tc.want, tc.n)
+		}
+	}
+}
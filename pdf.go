@@ -0,0 +1,517 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// PageSize is a PDF page size, in points (1/72 of an inch).
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	PageSizeA4     = PageSize{595.276, 841.89}
+	PageSizeLetter = PageSize{612, 792}
+)
+
+// PDFOptions are the page and font settings for RenderPDF.
+type PDFOptions struct {
+	PageSize PageSize
+
+	// Margin is the space, in points, between the text and each of the
+	// page's edges.
+	Margin float64
+
+	// Scale is how many points wide each of Render's pixels is.
+	Scale float64
+
+	// MiileeniolFont and EnglishFont are the TrueType fonts that the
+	// Miileeniol letters and the English are set in, and that are embedded in
+	// the PDF. The Miileeniol letters are spaced as per gomono, whatever
+	// their font. nil means gomono and goregular.
+	MiileeniolFont []byte
+	EnglishFont    []byte
+}
+
+// DefaultPDFOptions returns options for A4 pages, with half inch margins,
+// text at about 11 points and the Go fonts.
+func DefaultPDFOptions() PDFOptions {
+	return PDFOptions{
+		PageSize: PageSizeA4,
+		Margin:   36,
+		Scale:    0.4,
+	}
+}
+
+// RenderPDF is like Render but writes a PDF document to w, with the same two
+// column layout as Render's image, over as many pages as the text needs. The
+// Miileeniol letters and the English are text, in fonts embedded in the
+// document, and the diacritics and stress marks are vector paths. The
+// Renderer's Width and Height are ignored. Like Render, an incomplete
+// dictionary still produces a document, and the ErrNotInDictionary error is
+// returned after writing it.
+func (r *Renderer) RenderPDF(w io.Writer, text string, opts PDFOptions) error {
+	if opts.MiileeniolFont == nil {
+		opts.MiileeniolFont = gomono.TTF
+	}
+	if opts.EnglishFont == nil {
+		opts.EnglishFont = goregular.TTF
+	}
+	if !(opts.Scale > 0) || !(opts.Margin >= 0) {
+		return fmt.Errorf("miileeniol: invalid PDF scale %g or margin %g", opts.Scale, opts.Margin)
+	}
+	// The layout's page includes pageInset pixels on each side, which
+	// overlap the margins.
+	width := int((opts.PageSize.Width-2*opts.Margin)/opts.Scale) + 2*pageInset
+	height := int((opts.PageSize.Height-2*opts.Margin)/opts.Scale) + 2*pageInset
+	if (width < 4*pageInset) || (height < 2*pageInset+50) {
+		return fmt.Errorf("miileeniol: PDF page %gx%g is too small for its %g margins",
+			opts.PageSize.Width, opts.PageSize.Height, opts.Margin)
+	}
+
+	mono, err := newPDFFont("F1", opts.MiileeniolFont)
+	if err != nil {
+		return err
+	}
+	for _, g := range r.glyphs {
+		for _, c := range g.text {
+			if i, err := mono.font.GlyphIndex(&mono.buf, c); err != nil {
+				return err
+			} else if i == 0 {
+				return fmt.Errorf("miileeniol: PDF font %q has no glyph for %q", mono.baseFont, c)
+			}
+		}
+	}
+	english, err := newPDFFont("F2", opts.EnglishFont)
+	if err != nil {
+		return err
+	}
+
+	c := &pdfCanvas{
+		opts:    opts,
+		mono:    mono,
+		english: english,
+	}
+	c.newPage()
+	renderErr := r.layout(c, text, width, height)
+	if (renderErr != nil) && !errors.Is(renderErr, ErrNotInDictionary) {
+		return renderErr
+	} else if c.err != nil {
+		return c.err
+	}
+
+	if _, err := w.Write(c.document()); err != nil {
+		return err
+	}
+	return renderErr
+}
+
+// pdfFont is a TrueType font that is embedded in a PDF document, with every
+// glyph identified by its glyph index.
+type pdfFont struct {
+	// name is the font's name in the pages' resources.
+	name     string
+	baseFont string
+	data     []byte
+	font     *sfnt.Font
+	buf      sfnt.Buffer
+
+	// used maps the glyphs that a document uses to their runes.
+	used map[sfnt.GlyphIndex]rune
+}
+
+func newPDFFont(name string, data []byte) (*pdfFont, error) {
+	if (len(data) < 4) || ((string(data[:4]) != "\x00\x01\x00\x00") && (string(data[:4]) != "true")) {
+		return nil, errors.New("miileeniol: PDF font isn't a TrueType font")
+	}
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	x := &pdfFont{
+		name: name,
+		data: data,
+		font: f,
+		used: map[sfnt.GlyphIndex]rune{},
+	}
+
+	// The BaseFont is the PostScript name, which should be ASCII without
+	// delimiters, but make sure that it is.
+	ps, _ := f.Name(&x.buf, sfnt.NameIDPostScript)
+	x.baseFont = strings.Map(func(r rune) rune {
+		if (('0' <= r) && (r <= '9')) || (('A' <= r) && (r <= 'Z')) || (('a' <= r) && (r <= 'z')) || (r == '-') {
+			return r
+		}
+		return -1
+	}, ps)
+	if x.baseFont == "" {
+		x.baseFont = "Font" + name
+	}
+	return x, nil
+}
+
+// encode returns s as a PDF hex string of glyph indexes, each of which is 2
+// bytes, as per the Identity-H encoding. Runes missing from the font are
+// written as the .notdef glyph.
+func (f *pdfFont) encode(s string) (string, error) {
+	b := strings.Builder{}
+	b.WriteByte('<')
+	for _, r := range s {
+		i, err := f.font.GlyphIndex(&f.buf, r)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := f.used[i]; !ok {
+			f.used[i] = r
+		}
+		fmt.Fprintf(&b, "%04X", uint16(i))
+	}
+	b.WriteByte('>')
+	return b.String(), nil
+}
+
+// pdfCanvas is a canvas that writes PDF content streams, one per page.
+type pdfCanvas struct {
+	opts    PDFOptions
+	mono    *pdfFont
+	english *pdfFont
+
+	// pages are the pages' content streams, the last of which is the
+	// current page.
+	pages []*bytes.Buffer
+
+	// color is the current page's fill color, if hasColor.
+	color    color.NRGBA
+	hasColor bool
+
+	// err is the first error encoding text.
+	err error
+}
+
+func (c *pdfCanvas) newPage() {
+	b := &bytes.Buffer{}
+	c.pages = append(c.pages, b)
+	c.hasColor = false
+
+	// Map the layout's pixels, with the y axis pointing down, to the page's
+	// points, with the y axis pointing up, so that (pageInset, pageInset)
+	// is at the top-left margin.
+	s := c.opts.Scale
+	fmt.Fprintf(b, "%s 0 0 %s %s %s cm\n",
+		formatNumber(s), formatNumber(-s),
+		formatNumber(c.opts.Margin-s*pageInset),
+		formatNumber(c.opts.PageSize.Height-c.opts.Margin+s*pageInset))
+}
+
+func (c *pdfCanvas) page() *bytes.Buffer {
+	return c.pages[len(c.pages)-1]
+}
+
+func (c *pdfCanvas) setColor(col color.Color) {
+	n := color.NRGBAModel.Convert(col).(color.NRGBA)
+	if c.hasColor && (c.color == n) {
+		return
+	}
+	c.color, c.hasColor = n, true
+	fmt.Fprintf(c.page(), "%s %s %s rg\n",
+		formatNumber(float64(n.R)/0xFF), formatNumber(float64(n.G)/0xFF), formatNumber(float64(n.B)/0xFF))
+}
+
+// drawText draws s, in f at size pixels, with its baseline's left end at
+// (x, y).
+func (c *pdfCanvas) drawText(f *pdfFont, size float64, x int, y int, s string) {
+	e, err := f.encode(s)
+	if err != nil {
+		if c.err == nil {
+			c.err = err
+		}
+		return
+	}
+	// The text matrix flips the y axis back, so that the text is upright.
+	fmt.Fprintf(c.page(), "BT /%s %s Tf 1 0 0 -1 %d %d Tm %s Tj ET\n", f.name, formatNumber(size), x, y, e)
+}
+
+func (c *pdfCanvas) fillPath(p *pdfPath, col color.Color, evenOdd bool) {
+	if p.Len() == 0 {
+		return
+	}
+	c.setColor(col)
+	c.page().WriteString(p.String())
+	if evenOdd {
+		c.page().WriteString("f*\n")
+	} else {
+		c.page().WriteString("f\n")
+	}
+}
+
+func (c *pdfCanvas) drawGuide(rect image.Rectangle, col color.Color) {
+	c.setColor(col)
+	fmt.Fprintf(c.page(), "%d %d %d %d re f\n", rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy())
+}
+
+func (c *pdfCanvas) drawGlyph(x int, y int, col color.Color, g glyph) {
+	c.setColor(col)
+	// Place each rune on gomono's 16 pixel grid, as makeGlyphs does, even if
+	// the font's advance differs.
+	i := 0
+	for _, r := range g.text {
+		c.drawText(c.mono, 26.65, x+16*i, y+26, string(r))
+		i++
+	}
+	p := &pdfPath{}
+	addGlyphDiacritic(p, x, y, g)
+	c.fillPath(p, col, false)
+}
+
+func (c *pdfCanvas) drawStressMark(x int, y int, col color.Color, m StressMark) {
+	p := &pdfPath{}
+	evenOdd := addStressMark(p, x, y, m)
+	c.fillPath(p, col, evenOdd)
+}
+
+func (c *pdfCanvas) drawOverline(x0 int, x1 int, y int, col color.Color) {
+	p := &pdfPath{}
+	addOverline(p, x0, x1, y)
+	c.fillPath(p, col, false)
+}
+
+func (c *pdfCanvas) drawSyllableBoundary(x int, y int, col color.Color) {
+	p := &pdfPath{}
+	addSyllableBoundary(p, x, y)
+	c.fillPath(p, col, false)
+}
+
+func (c *pdfCanvas) drawEnglish(x int, y int, col color.Color, line string) {
+	c.setColor(col)
+	c.drawText(c.english, 26.5, x, y+26, line)
+}
+
+// pdfPath is a vectorPath that builds PDF path construction operators.
+type pdfPath struct {
+	bytes.Buffer
+
+	// x and y are the current point.
+	x, y float64
+}
+
+// op writes a path operator and its operands.
+func (p *pdfPath) op(operator string, operands ...float64) {
+	for _, o := range operands {
+		p.WriteString(formatNumber(o))
+		p.WriteByte(' ')
+	}
+	p.WriteString(operator)
+	p.WriteByte('\n')
+	if n := len(operands); n >= 2 {
+		p.x, p.y = operands[n-2], operands[n-1]
+	}
+}
+
+func (p *pdfPath) moveTo(x float64, y float64) { p.op("m", x, y) }
+func (p *pdfPath) lineTo(x float64, y float64) { p.op("l", x, y) }
+func (p *pdfPath) closePath()                  { p.op("h") }
+
+// quadTo adds a quadratic Bézier curve, which PDF doesn't have, as the
+// equivalent cubic curve.
+func (p *pdfPath) quadTo(x1 float64, y1 float64, x float64, y float64) {
+	p.cubeTo(
+		p.x+(x1-p.x)*2/3, p.y+(y1-p.y)*2/3,
+		x+(x1-x)*2/3, y+(y1-y)*2/3,
+		x, y)
+}
+
+func (p *pdfPath) cubeTo(x1 float64, y1 float64, x2 float64, y2 float64, x float64, y float64) {
+	p.op("c", x1, y1, x2, y2, x, y)
+}
+
+// pdfWriter writes a PDF document's numbered objects.
+type pdfWriter struct {
+	bytes.Buffer
+
+	// offsets are each object's offset, indexed by its number minus 1.
+	offsets []int
+}
+
+// reserve returns a new object number, for an object to be written later.
+func (w *pdfWriter) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+// object writes the object numbered n.
+func (w *pdfWriter) object(n int, format string, args ...interface{}) {
+	w.offsets[n-1] = w.Len()
+	fmt.Fprintf(w, "%d 0 obj\n", n)
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\nendobj\n")
+}
+
+// stream writes a new stream object, compressed, and returns its number.
+// extra are more entries for the stream's dictionary.
+func (w *pdfWriter) stream(data []byte, extra string) int {
+	z := &bytes.Buffer{}
+	zw := zlib.NewWriter(z)
+	zw.Write(data)
+	zw.Close()
+
+	n := w.reserve()
+	w.offsets[n-1] = w.Len()
+	fmt.Fprintf(w, "%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", n, z.Len(), extra)
+	w.Write(z.Bytes())
+	w.WriteString("\nendstream\nendobj\n")
+	return n
+}
+
+// document returns the PDF document with c's pages.
+func (c *pdfCanvas) document() []byte {
+	w := &pdfWriter{}
+	w.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	catalog, pages := w.reserve(), w.reserve()
+
+	fonts := strings.Builder{}
+	for _, f := range []*pdfFont{c.mono, c.english} {
+		fmt.Fprintf(&fonts, " /%s %d 0 R", f.name, w.font(f))
+	}
+
+	kids := strings.Builder{}
+	for i, content := range c.pages {
+		contents := w.stream(content.Bytes(), "")
+		page := w.reserve()
+		w.object(page, "<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s]"+
+			" /Resources << /Font <<%s >> >> /Contents %d 0 R >>",
+			pages, formatNumber(c.opts.PageSize.Width), formatNumber(c.opts.PageSize.Height),
+			fonts.String(), contents)
+		if i > 0 {
+			kids.WriteByte(' ')
+		}
+		fmt.Fprintf(&kids, "%d 0 R", page)
+	}
+	w.object(pages, "<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(c.pages))
+	w.object(catalog, "<< /Type /Catalog /Pages %d 0 R >>", pages)
+
+	xref := w.Len()
+	fmt.Fprintf(w, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, o := range w.offsets {
+		fmt.Fprintf(w, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(w, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalog, xref)
+	return w.Bytes()
+}
+
+// font writes f, as a Type 0 font whose glyphs are identified by their
+// glyph index, and returns its object number.
+func (w *pdfWriter) font(f *pdfFont) int {
+	upem := fixed.I(int(f.font.UnitsPerEm()))
+	// thousandths converts from font units to thousandths of an em.
+	thousandths := func(v fixed.Int26_6) int {
+		return int(int64(v) * 1000 / int64(upem))
+	}
+
+	glyphs := make([]sfnt.GlyphIndex, 0, len(f.used))
+	for i := range f.used {
+		glyphs = append(glyphs, i)
+	}
+	sort.Slice(glyphs, func(i int, j int) bool { return glyphs[i] < glyphs[j] })
+
+	widths := strings.Builder{}
+	for _, i := range glyphs {
+		advance, _ := f.font.GlyphAdvance(&f.buf, i, upem, font.HintingNone)
+		fmt.Fprintf(&widths, " %d [%d]", i, thousandths(advance))
+	}
+
+	bounds, _ := f.font.Bounds(&f.buf, upem, font.HintingNone)
+	metrics, _ := f.font.Metrics(&f.buf, upem, font.HintingNone)
+	flags := 32 // Nonsymbolic.
+	if i, err := f.font.GlyphIndex(&f.buf, 'i'); err == nil {
+		if j, err := f.font.GlyphIndex(&f.buf, 'W'); err == nil {
+			ai, _ := f.font.GlyphAdvance(&f.buf, i, upem, font.HintingNone)
+			aj, _ := f.font.GlyphAdvance(&f.buf, j, upem, font.HintingNone)
+			if ai == aj {
+				flags |= 1 // FixedPitch.
+			}
+		}
+	}
+
+	fontFile := w.stream(f.data, fmt.Sprintf(" /Length1 %d", len(f.data)))
+	descriptor := w.reserve()
+	w.object(descriptor, "<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d]"+
+		" /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.baseFont, flags,
+		thousandths(bounds.Min.X), thousandths(-bounds.Max.Y), thousandths(bounds.Max.X), thousandths(-bounds.Min.Y),
+		thousandths(metrics.Ascent), -thousandths(metrics.Descent), thousandths(metrics.CapHeight), fontFile)
+
+	cidFont := w.reserve()
+	w.object(cidFont, "<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s"+
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >>"+
+		" /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s ] >>",
+		f.baseFont, descriptor, widths.String())
+
+	toUnicode := w.stream(f.toUnicode(glyphs), "")
+	type0 := w.reserve()
+	w.object(type0, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H"+
+		" /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		f.baseFont, cidFont, toUnicode)
+	return type0
+}
+
+// toUnicode returns the CMap from glyphs, which are sorted, to their runes,
+// so that text can be copied from the document. The .notdef glyph, for runes
+// missing from the font, isn't mapped.
+func (f *pdfFont) toUnicode(glyphs []sfnt.GlyphIndex) []byte {
+	if (len(glyphs) > 0) && (glyphs[0] == 0) {
+		glyphs = glyphs[1:]
+	}
+	b := &bytes.Buffer{}
+	b.WriteString("/CIDInit /ProcSet findresource begin\n" +
+		"12 dict begin\n" +
+		"begincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n" +
+		"/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// A bfchar section has at most 100 entries.
+	for len(glyphs) > 0 {
+		n := len(glyphs)
+		if n > 100 {
+			n = 100
+		}
+		fmt.Fprintf(b, "%d beginbfchar\n", n)
+		for _, i := range glyphs[:n] {
+			fmt.Fprintf(b, "<%04X> <", uint16(i))
+			for _, u := range utf16.Encode([]rune{f.used[i]}) {
+				fmt.Fprintf(b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+		glyphs = glyphs[n:]
+	}
+
+	b.WriteString("endcmap\n" +
+		"CMapName currentdict /CMap defineresource pop\n" +
+		"end\n" +
+		"end\n")
+	return b.Bytes()
+}
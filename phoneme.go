@@ -0,0 +1,106 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stress is a phoneme's stress.
+type Stress uint8
+
+const (
+	Unstressed Stress = iota
+	PrimaryStress
+	SecondaryStress
+)
+
+// The IPA marks for stress and length.
+const (
+	primaryStressMark   = "ˈ"
+	secondaryStressMark = "ˌ"
+	lengthMark          = "ː"
+)
+
+// Phoneme is one phoneme of a pronunciation, or a punctuation mark, which is
+// written as itself.
+type Phoneme struct {
+	// Symbol is the phoneme's IPA symbol, without stress or length marks,
+	// such as "k", "ɑ", "aɪ" or "tʃ", or the punctuation mark, such as ",".
+	// It indexes an Alphabet's maps.
+	Symbol string
+
+	Stress Stress
+
+	// Long is whether the phoneme has a length mark, as in "ɑː".
+	Long bool
+}
+
+// String returns p in Britfone's format, such as "ˈɑː".
+func (p Phoneme) String() string {
+	s := p.Symbol
+	switch p.Stress {
+	case PrimaryStress:
+		s = primaryStressMark + s
+	case SecondaryStress:
+		s = secondaryStressMark + s
+	}
+	if p.Long {
+		s += lengthMark
+	}
+	return s
+}
+
+// IsVowel returns whether p is a vowel (including a diphthong).
+func (p Phoneme) IsVowel() bool {
+	return strings.ContainsAny(p.Symbol, "aeiouæɐɑɒɔəɛɜɪʊ")
+}
+
+// ParsePhoneme parses one phoneme in Britfone's format, such as "ˈɑː": an
+// IPA symbol, optionally prefixed by a stress mark and suffixed by a length
+// mark.
+func ParsePhoneme(s string) (Phoneme, error) {
+	p := Phoneme{Symbol: s}
+	if strings.HasPrefix(p.Symbol, primaryStressMark) {
+		p.Symbol, p.Stress = p.Symbol[len(primaryStressMark):], PrimaryStress
+	} else if strings.HasPrefix(p.Symbol, secondaryStressMark) {
+		p.Symbol, p.Stress = p.Symbol[len(secondaryStressMark):], SecondaryStress
+	}
+	if strings.HasSuffix(p.Symbol, lengthMark) {
+		p.Symbol, p.Long = p.Symbol[:len(p.Symbol)-len(lengthMark)], true
+	}
+	if (p.Symbol == "") || strings.ContainsAny(p.Symbol, primaryStressMark+secondaryStressMark+lengthMark+" ") {
+		return Phoneme{}, fmt.Errorf("miileeniol: bad phoneme %q", s)
+	}
+	return p, nil
+}
+
+// ParsePronunciation splits a pronunciation in Britfone's format, such as
+// "ð ˈɪ s", into its space-separated phonemes, as per ParsePhoneme.
+func ParsePronunciation(s string) ([]Phoneme, error) {
+	fields := strings.Fields(s)
+	phonemes := make([]Phoneme, 0, len(fields))
+	for _, field := range fields {
+		p, err := ParsePhoneme(field)
+		if err != nil {
+			return nil, err
+		}
+		phonemes = append(phonemes, p)
+	}
+	return phonemes, nil
+}
+
+// FormatPronunciation is the inverse of ParsePronunciation.
+func FormatPronunciation(phonemes []Phoneme) string {
+	sb := strings.Builder{}
+	for i, p := range phonemes {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(p.String())
+	}
+	return sb.String()
+}
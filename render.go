@@ -0,0 +1,575 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/golang/freetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Renderer draws text as a two column image: Miileeniol on the left and the
+// original English on the right. A Renderer is not safe for concurrent use.
+type Renderer struct {
+	Transliterator *Transliterator
+
+	// Width and Height are the image size, in pixels.
+	Width  int
+	Height int
+
+	// Foreground is the Miileeniol color. English is the English color.
+	// Guessed is the color of words whose pronunciation was guessed.
+	Foreground color.Color
+	English    color.Color
+	Guessed    color.Color
+
+	// PrimaryStressMark and SecondaryStressMark are drawn below letters with
+	// primary or secondary stress. NewRenderer sets them to StressMarkDot
+	// and StressMarkNone.
+	PrimaryStressMark   StressMark
+	SecondaryStressMark StressMark
+
+	// SyllableBoundaries is whether to draw a raised dot between each
+	// word's syllables.
+	SyllableBoundaries bool
+
+	// RomanOutput, if non-nil, receives the romanization of each rendered
+	// word, one line per rendered line.
+	RomanOutput io.Writer
+
+	glyphs   map[string]glyph
+	codaDrop int
+	goreg    *freetype.Context
+}
+
+// glyph is how a letter is drawn.
+type glyph struct {
+	mask *image.Alpha
+
+	// text is the letter's gomono text and diacritic is its '\'' or '~'
+	// diacritic, or 0 if it has none.
+	text      string
+	diacritic byte
+
+	// joinedOverline is whether the letter has an overline that isn't part
+	// of the mask, as it can join those of its neighbors.
+	joinedOverline bool
+
+	// vowel is whether the letter is for a vowel.
+	vowel bool
+}
+
+// StressMark is how a Renderer marks a stressed letter, below it.
+type StressMark int
+
+const (
+	StressMarkNone StressMark = iota
+	StressMarkDot
+	StressMarkRing
+	StressMarkBar
+)
+
+// NewRenderer returns a Renderer that uses t and the default page size and
+// colors. The glyphs are drawn from t's Alphabet as it is now; changing that
+// Alphabet afterwards does not affect the Renderer.
+func NewRenderer(t *Transliterator) (*Renderer, error) {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	goreg := freetype.NewContext()
+	goreg.SetDPI(72)
+	goreg.SetFont(f)
+	goreg.SetFontSize(26.5)
+	goreg.SetHinting(font.HintingFull)
+
+	glyphs, err := makeGlyphs(t.Alphabet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		Transliterator:    t,
+		Width:             256 * 7,
+		Height:            256 * 5,
+		Foreground:        color.RGBA{0x00, 0x00, 0x7F, 0xFF},
+		English:           color.RGBA{0x7F, 0x00, 0x00, 0xFF},
+		Guessed:           color.RGBA{0x00, 0x7F, 0x7F, 0xFF},
+		PrimaryStressMark: StressMarkDot,
+		glyphs:            glyphs,
+		codaDrop:          t.Alphabet.CodaDrop,
+		goreg:             goreg,
+	}, nil
+}
+
+func makeGlyphs(a *Alphabet) (map[string]glyph, error) {
+	f, err := freetype.ParseFont(gomono.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	c := freetype.NewContext()
+	c.SetDPI(72)
+	c.SetFont(f)
+	c.SetFontSize(26.65)
+	c.SetSrc(image.White)
+	c.SetHinting(font.HintingFull)
+
+	glyphs := map[string]glyph{}
+	for r, cluster := range a.Letters {
+		vowel := Phoneme{Symbol: strings.TrimSuffix(r, lengthMark)}.IsVowel()
+		if cluster == "" {
+			continue
+		}
+		s, diacritic := cluster[:len(cluster)-1], cluster[len(cluster)-1]
+		if (s == "") || ((diacritic != '\'') && (diacritic != '~')) {
+			s, diacritic = cluster, 0
+		}
+		for _, c := range s {
+			if f.Index(c) == 0 {
+				return nil, fmt.Errorf("miileeniol: alphabet %q has no glyph for %q (in %q)", a.Name, c, r)
+			}
+		}
+
+		width := 16 * utf8.RuneCountInString(s)
+		m := image.NewGray(image.Rect(0, 0, width, 28))
+		c.SetClip(m.Bounds())
+		c.SetDst(m)
+		if _, err := c.DrawString(s, freetype.Pt(0, 26)); err != nil {
+			return nil, err
+		}
+
+		if false {
+			// Change s/false/true/ in the line above to draw plain vowels,
+			// without diacritics.
+
+		} else if diacritic == '\'' {
+			const y = 0
+			const x = 0
+			m.SetGray(x+7, y+5, color.Gray{0xFF})
+			m.SetGray(x+8, y+5, color.Gray{0xFF})
+			m.SetGray(x+6, y+6, color.Gray{0xFF})
+			m.SetGray(x+7, y+6, color.Gray{0xFF})
+			m.SetGray(x+8, y+6, color.Gray{0xFF})
+			m.SetGray(x+9, y+6, color.Gray{0xFF})
+			m.SetGray(x+6, y+7, color.Gray{0xFF})
+			m.SetGray(x+7, y+7, color.Gray{0xFF})
+			m.SetGray(x+8, y+7, color.Gray{0xFF})
+			m.SetGray(x+9, y+7, color.Gray{0xFF})
+			m.SetGray(x+6, y+8, color.Gray{0xFF})
+			m.SetGray(x+7, y+8, color.Gray{0xFF})
+			m.SetGray(x+8, y+8, color.Gray{0xFF})
+			m.SetGray(x+9, y+8, color.Gray{0xFF})
+			m.SetGray(x+7, y+9, color.Gray{0xFF})
+			m.SetGray(x+8, y+9, color.Gray{0xFF})
+
+		} else if diacritic == '~' && a.JoinOverlines {
+			// The overline is drawn by drawWord.
+
+		} else if diacritic == '~' {
+			y := overlineY(vowel)
+			x0 := 3
+			x1 := width - 2
+			for ; x0 < x1; x0++ {
+				m.SetGray(x0, y+0, color.Gray{0xFF})
+				m.SetGray(x0, y+1, color.Gray{0xFF})
+			}
+			for x0 = x1 - 2; x0 < x1; x0++ {
+				m.SetGray(x0, y+2, color.Gray{0xFF})
+			}
+		}
+
+		glyphs[r] = glyph{
+			mask: &image.Alpha{
+				Pix:    m.Pix,
+				Stride: m.Stride,
+				Rect:   m.Rect,
+			},
+			text:           s,
+			diacritic:      diacritic,
+			joinedOverline: (diacritic == '~') && a.JoinOverlines,
+			vowel:          vowel,
+		}
+	}
+	return glyphs, nil
+}
+
+// canvas is what a Renderer lays text out on, in pixels with the y axis
+// pointing down: an image or a vector format.
+type canvas interface {
+	// drawGuide fills rect, part of a guideline.
+	drawGuide(rect image.Rectangle, c color.Color)
+
+	// drawGlyph draws g with its top-left corner at (x, y).
+	drawGlyph(x int, y int, c color.Color, g glyph)
+
+	// drawStressMark draws m below the letter whose top-left corner is at
+	// (x, y).
+	drawStressMark(x int, y int, c color.Color, m StressMark)
+
+	// drawOverline draws an overline, ending in a short downward tick,
+	// spanning [x0, x1).
+	drawOverline(x0 int, x1 int, y int, c color.Color)
+
+	// drawSyllableBoundary draws a raised dot, in the syllableGap after x.
+	drawSyllableBoundary(x int, y int, c color.Color)
+
+	// drawEnglish draws line, which has no markers or trailing newline, with
+	// its top-left corner at (x, y).
+	drawEnglish(x int, y int, c color.Color, line string)
+}
+
+// pager is a canvas with more than one page. Text that doesn't fit on a
+// pager's page continues on the next page, instead of being cut off.
+type pager interface {
+	// newPage starts a new, blank, page.
+	newPage()
+}
+
+// imageCanvas is a canvas that draws on an image.
+type imageCanvas struct {
+	dst   *image.RGBA
+	goreg *freetype.Context
+}
+
+func (m *imageCanvas) drawGuide(rect image.Rectangle, c color.Color) {
+	draw.Draw(m.dst, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func (m *imageCanvas) drawGlyph(x int, y int, c color.Color, g glyph) {
+	draw.DrawMask(m.dst, m.dst.Bounds().Add(image.Point{x, y}),
+		&image.Uniform{C: c}, image.Point{}, g.mask, image.Point{}, draw.Over)
+}
+
+func (m *imageCanvas) drawEnglish(x int, y int, c color.Color, line string) {
+	m.goreg.SetClip(m.dst.Bounds())
+	m.goreg.SetDst(m.dst)
+	m.goreg.SetSrc(&image.Uniform{C: c})
+	m.goreg.DrawString(line, freetype.Pt(x, y+26))
+}
+
+func (m *imageCanvas) drawStressMark(x int, y int, c color.Color, s StressMark) {
+	switch s {
+	case StressMarkDot:
+		m.drawLowDot(x, y, c)
+	case StressMarkRing:
+		m.drawLowRing(x, y, c)
+	case StressMarkBar:
+		m.drawLowBar(x, y, c)
+	}
+}
+
+func (m *imageCanvas) drawLowDot(x int, y int, c color.Color) {
+	dst := m.dst
+	dst.Set(x+7, y+28, c)
+	dst.Set(x+8, y+28, c)
+	dst.Set(x+6, y+29, c)
+	dst.Set(x+7, y+29, c)
+	dst.Set(x+8, y+29, c)
+	dst.Set(x+9, y+29, c)
+	dst.Set(x+6, y+30, c)
+	dst.Set(x+7, y+30, c)
+	dst.Set(x+8, y+30, c)
+	dst.Set(x+9, y+30, c)
+	dst.Set(x+6, y+31, c)
+	dst.Set(x+7, y+31, c)
+	dst.Set(x+8, y+31, c)
+	dst.Set(x+9, y+31, c)
+	dst.Set(x+7, y+32, c)
+	dst.Set(x+8, y+32, c)
+}
+
+func (m *imageCanvas) drawLowRing(x int, y int, c color.Color) {
+	dst := m.dst
+	for i := 0; i < 2; i++ {
+		dst.Set(x+7+i, y+28, c)
+		dst.Set(x+7+i, y+33, c)
+		dst.Set(x+5, y+30+i, c)
+		dst.Set(x+10, y+30+i, c)
+	}
+	dst.Set(x+6, y+29, c)
+	dst.Set(x+9, y+29, c)
+	dst.Set(x+6, y+32, c)
+	dst.Set(x+9, y+32, c)
+}
+
+func (m *imageCanvas) drawLowBar(x int, y int, c color.Color) {
+	dst := m.dst
+	for i := 4; i < 12; i++ {
+		dst.Set(x+i, y+30, c)
+		dst.Set(x+i, y+31, c)
+	}
+}
+
+func (m *imageCanvas) drawOverline(x0 int, x1 int, y int, c color.Color) {
+	dst := m.dst
+	for x := x0; x < x1; x++ {
+		dst.Set(x, y+0, c)
+		dst.Set(x, y+1, c)
+	}
+	for x := x1 - 2; x < x1; x++ {
+		dst.Set(x, y+2, c)
+	}
+}
+
+func (m *imageCanvas) drawSyllableBoundary(x int, y int, c color.Color) {
+	dst := m.dst
+	for i := 0; i < 2; i++ {
+		dst.Set(x+2, y+16+i, c)
+		dst.Set(x+3, y+16+i, c)
+	}
+}
+
+// overlineY is the y offset of a letter's overline: above lower case
+// letters, for vowels, or above upper case letters, for consonants.
+func overlineY(vowel bool) int {
+	if vowel {
+		return 7
+	}
+	return 2
+}
+
+// syllableGap is the extra width, in pixels, of a syllable boundary.
+const syllableGap = 6
+
+// drawWord draws w at (x, y) on c, or only measures it if c is nil.
+func (r *Renderer) drawWord(c canvas, x int, y int, fg color.Color, w Word) (newX int, err error) {
+	syllable, down := 0, 0
+
+	// barX0 and barY are where the current joined overline starts, if barX0
+	// is non-negative.
+	barX0, barY := -1, 0
+	endBar := func() {
+		if barX0 >= 0 {
+			if c != nil {
+				c.drawOverline(barX0, x-2, barY, fg)
+			}
+			barX0 = -1
+		}
+	}
+
+	for i, l := range w.Letters {
+		g, ok := r.glyphs[alphabetKey(l, func(k string) bool {
+			_, ok := r.glyphs[k]
+			return ok
+		})]
+		if !ok {
+			return x, fmt.Errorf("miileeniol: couldn't draw %q", w.English)
+		}
+
+		for ; (syllable < len(w.Syllables)) && (w.Syllables[syllable].End <= i); syllable++ {
+		}
+		inSyllable := (syllable < len(w.Syllables)) && (w.Syllables[syllable].Start <= i)
+		if !inSyllable {
+			down = 0
+		} else if w.Syllables[syllable].Start == i {
+			endBar()
+			down = 0
+			if r.SyllableBoundaries && (syllable > 0) && (w.Syllables[syllable-1].End == i) {
+				if c != nil {
+					c.drawSyllableBoundary(x, y, fg)
+				}
+				x += syllableGap
+			}
+		}
+
+		if !g.joinedOverline {
+			endBar()
+		} else if barX0 < 0 {
+			barX0, barY = x+3, y+down+overlineY(g.vowel)
+		}
+
+		if c != nil {
+			c.drawGlyph(x, y+down, fg, g)
+
+			switch l.Stress {
+			case PrimaryStress:
+				c.drawStressMark(x, y+down, fg, r.PrimaryStressMark)
+			case SecondaryStress:
+				c.drawStressMark(x, y+down, fg, r.SecondaryStressMark)
+			}
+		}
+
+		x += (g.mask.Bounds().Dx() * 15 / 16)
+		if inSyllable && g.vowel {
+			down = r.codaDrop
+		}
+	}
+	endBar()
+
+	if (c != nil) && (r.RomanOutput != nil) {
+		if c, _ := utf8.DecodeRuneInString(w.English); isAlpha(c) || isNumeral(w.English) {
+			io.WriteString(r.RomanOutput, r.Transliterator.Romanize(w)+" ")
+		}
+	}
+	return x, nil
+}
+
+// pageInset is the margin, in pixels, around each of a page's columns.
+const pageInset = 25
+
+// Render draws text. Words missing from the dictionary are skipped, and
+// Render then returns the (incomplete) image along with an error that wraps
+// ErrNotInDictionary and lists every missing word.
+func (r *Renderer) Render(text string) (*image.RGBA, error) {
+	rgba := image.NewRGBA(image.Rect(0, 0, r.Width, r.Height))
+	draw.Draw(rgba, rgba.Bounds(), image.White, image.ZP, draw.Src)
+
+	if err := r.layout(&imageCanvas{rgba, r.goreg}, text, r.Width, r.Height); (err != nil) && !errors.Is(err, ErrNotInDictionary) {
+		return nil, err
+	} else if err != nil {
+		return rgba, err
+	}
+	return rgba, nil
+}
+
+// layout lays text out on c, as two columns, each imageWidth/2 pixels wide:
+// Miileeniol on the left and the original English on the right. Missing
+// words are skipped, as per Render.
+func (r *Renderer) layout(c canvas, text string, imageWidth int, imageHeight int) error {
+	if r.Transliterator.Heteronyms {
+		text, _ = r.Transliterator.Disambiguate(text)
+	}
+
+	fg := r.Foreground
+	red := r.English
+	guessed := r.Guessed
+
+	// Draw guidelines.
+	drawGuides := func() {
+		guide := color.RGBA{0xDD, 0xDD, 0xDD, 0xFF}
+		for y := 25 + pageInset; y < imageHeight; y += 50 {
+			c.drawGuide(image.Rect(0, y, imageWidth, y+1), guide)
+		}
+		if true {
+			c.drawGuide(image.Rect((imageWidth/2), 0, (imageWidth/2)+1, imageHeight), guide)
+		}
+	}
+	drawGuides()
+
+	newLine := func() {
+		if r.RomanOutput != nil {
+			io.WriteString(r.RomanOutput, "\n")
+		}
+	}
+
+	// Render glyphs.
+	missing := []string(nil)
+	{
+		originalText := text
+
+		x, y, s := pageInset, pageInset, text
+
+		// fit starts a new page, if c has pages, when the line at y
+		// doesn't fit on the current one.
+		fit := func() {
+			if p, ok := c.(pager); ok && (y > pageInset) && (y+50 > imageHeight) {
+				p.newPage()
+				drawGuides()
+				y = pageInset
+			}
+		}
+
+		// drawEnglish draws the English of the line at y.
+		drawEnglish := func(line string) {
+			if line = StripMarkers(strings.TrimRight(line, "\r\n")); strings.TrimSpace(line) == "" {
+				return
+			}
+			fit()
+			c.drawEnglish((imageWidth/2)+pageInset, y, red, line)
+		}
+
+		for s != "" {
+			if ch := s[0]; (ch == ' ') || (ch == '\t') {
+				x += 15
+				s = s[1:]
+				continue
+			} else if ch == '\n' {
+				s = s[1:]
+				line := originalText[:len(originalText)-len(s)]
+				originalText = originalText[len(line):]
+				drawEnglish(line)
+
+				x = pageInset
+				y += 50
+				newLine()
+				continue
+			} else if ch < ' ' {
+				// Skip other control characters, such as the '\r' of "\r\n",
+				// as TransliterateText does.
+				s = s[1:]
+				continue
+			}
+
+			w, remaining, err := r.Transliterator.TransliterateNext(s)
+			if errors.Is(err, ErrNotInDictionary) {
+				missing = append(missing, w.English)
+				s = remaining
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			x1, err := r.drawWord(nil, x, y, fg, w)
+			if err != nil {
+				return err
+			}
+			if (x > pageInset) && (x1 > ((imageWidth / 2) - pageInset)) {
+				line := originalText[:len(originalText)-len(s)]
+				originalText = originalText[len(line):]
+				drawEnglish(line)
+
+				x, y = pageInset, y+50
+				newLine()
+			}
+			fit()
+			wordFg := fg
+			if w.Guessed {
+				wordFg = guessed
+			}
+			if x, err = r.drawWord(c, x, y, wordFg, w); err != nil {
+				return err
+			}
+			s = remaining
+		}
+		// Text that doesn't end with a newline still has its last line's
+		// English.
+		drawEnglish(originalText)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %q", ErrNotInDictionary, missing)
+	}
+	return nil
+}
+
+// RenderPNG is like Render but writes the image as PNG to w. Like Render, an
+// incomplete dictionary still produces an image, and the ErrNotInDictionary
+// error is returned after writing it.
+func (r *Renderer) RenderPNG(w io.Writer, text string) error {
+	rgba, renderErr := r.Render(text)
+	if rgba == nil {
+		return renderErr
+	}
+	b := bufio.NewWriter(w)
+	if err := png.Encode(b, rgba); err != nil {
+		return err
+	}
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	return renderErr
+}
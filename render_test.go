@@ -0,0 +1,61 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderControlCharacters(t *testing.T) {
+	d, err := NewEmbeddedDictionary()
+	if err != nil {
+		t.Fatalf("NewEmbeddedDictionary: %v", err)
+	}
+	r, err := NewRenderer(NewTransliterator(d, NewDefaultAlphabet()))
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	// renderSVG renders text, failing rather than hanging if layout doesn't
+	// advance past a character.
+	renderSVG := func(text string) string {
+		buf := &bytes.Buffer{}
+		done := make(chan error, 1)
+		go func() {
+			done <- r.RenderSVG(buf, text)
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("RenderSVG(%q): %v", text, err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("RenderSVG(%q): timed out", text)
+		}
+		return buf.String()
+	}
+
+	// A tab is a space, but the English text keeps it, so compare the SVGs
+	// without their text elements.
+	withoutText := func(svg string) string {
+		lines := strings.Split(svg, "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, "<text ") {
+				lines[i] = ""
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	if got, want := withoutText(renderSVG("cat\tdog\n")), withoutText(renderSVG("cat dog\n")); got != want {
+		t.Errorf("tab: got a different SVG than for a space")
+	}
+	if got, want := renderSVG("cat dog\r\nthe end\r\n"), renderSVG("cat dog\nthe end\n"); got != want {
+		t.Errorf("CRLF: got a different SVG than for LF")
+	}
+	renderSVG("cat\x00\x1bdog")
+}
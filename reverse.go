@@ -0,0 +1,252 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReverseIndex reads romanized Miileeniol back as English. It maps every
+// pronunciation in a Transliterator's Dictionary, after its AccentProfile, to
+// the words with that pronunciation.
+type ReverseIndex struct {
+	t *Transliterator
+
+	// entries are keyed by their romanization, without stress marks.
+	entries map[string][]reverseEntry
+}
+
+type reverseEntry struct {
+	key      string
+	phonemes []Phoneme
+}
+
+// Candidate is an English word that a romanized word can be read as.
+type Candidate struct {
+	// English is the upper-case English word, such as "THEIR" or "COSTA
+	// RICA".
+	English string
+
+	// Key is the word's dictionary key, such as "READ(2)".
+	Key string
+
+	// Pronunciation is the word's pronunciation, in Britfone's format, after
+	// the Transliterator's AccentProfile.
+	Pronunciation string
+}
+
+// Reversal is part of a romanized text read back as English: either a word
+// or the whitespace and punctuation between words.
+type Reversal struct {
+	// Text is the whitespace and punctuation or, for a word, its
+	// romanization.
+	Text string
+
+	// Phonemes are the word's phonemes, or nil for whitespace and
+	// punctuation.
+	Phonemes []Phoneme
+
+	// Candidates are the English words that the word can be read as, best
+	// first, as per ReverseIndex.Lookup.
+	Candidates []Candidate
+}
+
+// NewReverseIndex returns a ReverseIndex over t's Dictionary. Romanized text
+// is read with t's Alphabet and RomanStress. Changing t afterwards does not
+// affect the ReverseIndex.
+func NewReverseIndex(t *Transliterator) *ReverseIndex {
+	x := &ReverseIndex{
+		t:       &Transliterator{Alphabet: t.Alphabet, RomanStress: t.RomanStress},
+		entries: map[string][]reverseEntry{},
+	}
+	for _, k := range t.Dictionary.Keys() {
+		v, _ := t.Dictionary.Lookup(k)
+		phonemes, err := ParsePronunciation(t.AccentProfile.Rewrite(v))
+		if (err != nil) || (len(phonemes) == 0) {
+			continue
+		}
+		undrawable := false
+		for _, p := range phonemes {
+			if _, ok := t.Alphabet.roman(p); !ok {
+				undrawable = true
+				break
+			}
+		}
+		if undrawable {
+			continue
+		}
+		roman := t.Alphabet.Romanize(phonemes, RomanStressNone)
+		x.entries[roman] = append(x.entries[roman], reverseEntry{k, phonemes})
+	}
+	return x
+}
+
+// Lookup returns the English words that the romanization of a word, without
+// punctuation, can be read as: the words with the same phonemes. Homophones
+// are all returned, ranked by:
+//   - whether their stress is that of the romanized word, if it marks
+//     stress,
+//   - whether it is their main pronunciation, not a numbered variant such
+//     as "READ(2)",
+//   - whether they are spelled with only letters,
+//   - shortest spelling first, as common words tend to be short, and then
+//   - alphabetical order.
+func (x *ReverseIndex) Lookup(roman string) ([]Candidate, error) {
+	phonemes, err := x.t.Alphabet.ParseRoman(roman, x.t.RomanStress)
+	if err != nil {
+		return nil, err
+	}
+	return x.LookupPhonemes(phonemes), nil
+}
+
+// LookupPhonemes is like Lookup but takes the word's phonemes.
+func (x *ReverseIndex) LookupPhonemes(phonemes []Phoneme) []Candidate {
+	entries := x.entries[x.t.Alphabet.Romanize(phonemes, RomanStressNone)]
+	if len(entries) == 0 {
+		return nil
+	}
+
+	type ranked struct {
+		Candidate
+		stressMismatch bool
+		variant        bool
+		nonLetters     bool
+	}
+	rs := make([]ranked, 0, len(entries))
+	for _, e := range entries {
+		base, n := splitVariant(e.key)
+		if i := strings.IndexByte(base, '%'); i > 0 {
+			base = base[:i]
+		}
+		english := strings.Replace(base, "_", " ", -1)
+		rs = append(rs, ranked{
+			Candidate: Candidate{
+				English:       english,
+				Key:           e.key,
+				Pronunciation: FormatPronunciation(e.phonemes),
+			},
+			stressMismatch: !x.stressMatches(phonemes, e.phonemes),
+			variant:        n > 1,
+			nonLetters:     strings.IndexFunc(english, func(r rune) bool { return !isAlpha(r) }) >= 0,
+		})
+	}
+	sort.Slice(rs, func(i int, j int) bool {
+		if rs[i].stressMismatch != rs[j].stressMismatch {
+			return !rs[i].stressMismatch
+		} else if rs[i].variant != rs[j].variant {
+			return !rs[i].variant
+		} else if rs[i].nonLetters != rs[j].nonLetters {
+			return !rs[i].nonLetters
+		} else if len(rs[i].English) != len(rs[j].English) {
+			return len(rs[i].English) < len(rs[j].English)
+		} else if rs[i].English != rs[j].English {
+			return rs[i].English < rs[j].English
+		}
+		return rs[i].Key < rs[j].Key
+	})
+
+	candidates := make([]Candidate, 0, len(rs))
+	seen := map[string]bool{}
+	for _, r := range rs {
+		if !seen[r.English] {
+			seen[r.English] = true
+			candidates = append(candidates, r.Candidate)
+		}
+	}
+	return candidates
+}
+
+// stressMatches returns whether the stress of the dictionary's phonemes is
+// that of the romanized word's, as far as x.t.RomanStress marks it.
+func (x *ReverseIndex) stressMatches(romanized []Phoneme, dictionary []Phoneme) bool {
+	for i, p := range romanized {
+		switch x.t.RomanStress {
+		case RomanStressDigits:
+			if p.Stress != dictionary[i].Stress {
+				return false
+			}
+		case RomanStressUpper:
+			if (p.Stress == PrimaryStress) != (dictionary[i].Stress == PrimaryStress) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ReverseText reads romanized text back as English. The text is split into
+// words, each of which is looked up as per Lookup, and the whitespace and
+// punctuation between them. Concatenating the Reversals' Text gives the text
+// back, other than for letters' case and separators. Words with no
+// candidates are kept, and the returned error then wraps ErrNotInDictionary
+// and lists every such word. Any other error, such as text that isn't
+// romanized with the Alphabet, is returned immediately.
+func (x *ReverseIndex) ReverseText(text string) ([]Reversal, error) {
+	rs := []Reversal(nil)
+	missing := []string(nil)
+	between := ""
+
+	for s := text; s != ""; {
+		if r, n := utf8.DecodeRuneInString(s); r <= ' ' {
+			between += s[:n]
+			s = s[n:]
+			continue
+		}
+		n := strings.IndexFunc(s, func(r rune) bool { return r <= ' ' })
+		if n < 0 {
+			n = len(s)
+		}
+		phonemes, err := x.t.Alphabet.ParseRoman(s[:n], x.t.RomanStress)
+		if err != nil {
+			return nil, err
+		}
+		s = s[n:]
+
+		for len(phonemes) > 0 {
+			i := 0
+			for ; i < len(phonemes); i++ {
+				if _, ok := x.t.Alphabet.roman(phonemes[i]); ok {
+					break
+				}
+				between += phonemes[i].Symbol
+			}
+			j := i
+			for ; j < len(phonemes); j++ {
+				if _, ok := x.t.Alphabet.roman(phonemes[j]); !ok {
+					break
+				}
+			}
+			if i == j {
+				break
+			}
+
+			if between != "" {
+				rs = append(rs, Reversal{Text: between})
+				between = ""
+			}
+			w := Reversal{
+				Text:       x.t.Alphabet.Romanize(phonemes[i:j], x.t.RomanStress),
+				Phonemes:   phonemes[i:j],
+				Candidates: x.LookupPhonemes(phonemes[i:j]),
+			}
+			if len(w.Candidates) == 0 {
+				missing = append(missing, w.Text)
+			}
+			rs = append(rs, w)
+			phonemes = phonemes[j:]
+		}
+	}
+	if between != "" {
+		rs = append(rs, Reversal{Text: between})
+	}
+
+	if len(missing) > 0 {
+		return rs, fmt.Errorf("%w: %q", ErrNotInDictionary, missing)
+	}
+	return rs, nil
+}
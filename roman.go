@@ -0,0 +1,121 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// RomanStress is how a romanization marks stress.
+type RomanStress int
+
+const (
+	// RomanStressNone doesn't mark stress.
+	RomanStressNone RomanStress = iota
+
+	// RomanStressDigits follows a vowel with primary stress by "1" and one
+	// with secondary stress by "2", as CMUdict does: "oo1baut" is "about".
+	RomanStressDigits
+
+	// RomanStressUpper writes a vowel with primary stress in upper case:
+	// "oobAUt" is "about". Secondary stress isn't marked.
+	RomanStressUpper
+)
+
+// romanSeparator separates two letters' romanizations that would otherwise
+// be read as one letter, as in "niat'xeel" ("nutshell", with /t ʃ/ instead of
+// /tʃ/).
+const romanSeparator = '\''
+
+// Romanize returns the ASCII romanization of phonemes, such as a Word's
+// Letters, with stress marked as per stress. Punctuation is passed through.
+// ParseRoman reads the romanization back.
+func (a *Alphabet) Romanize(phonemes []Phoneme, stress RomanStress) string {
+	// Work backwards, so that a letter's romanization can be checked against
+	// what follows it.
+	s := ""
+	for i := len(phonemes) - 1; i >= 0; i-- {
+		p := phonemes[i]
+		roman, ok := a.roman(p)
+		if !ok {
+			if utf8.RuneCountInString(p.Symbol) == 1 {
+				s = p.Symbol + s
+			}
+			continue
+		}
+		if p.IsVowel() {
+			switch {
+			case (stress == RomanStressDigits) && (p.Stress == PrimaryStress):
+				roman += "1"
+			case (stress == RomanStressDigits) && (p.Stress == SecondaryStress):
+				roman += "2"
+			case (stress == RomanStressUpper) && (p.Stress == PrimaryStress):
+				roman = strings.ToUpper(roman)
+			}
+		}
+		if (s != "") && (s[0] != romanSeparator) {
+			if _, n := a.readRoman(roman + s); n > len(roman) {
+				roman += string(romanSeparator)
+			}
+		}
+		s = roman + s
+	}
+	return s
+}
+
+// ParseRoman parses the romanization of a word, as returned by Romanize with
+// the same stress, back into phonemes. Upper and lower case romanizations are
+// equivalent, other than for RomanStressUpper.
+func (a *Alphabet) ParseRoman(s string, stress RomanStress) ([]Phoneme, error) {
+	phonemes := []Phoneme(nil)
+	for i, afterLetter := 0, false; i < len(s); {
+		if key, n := a.readRoman(s[i:]); n > 0 {
+			p, err := ParsePhoneme(key)
+			if err != nil {
+				return nil, err
+			}
+			if p.IsVowel() {
+				switch {
+				case (stress == RomanStressDigits) && (i+n < len(s)) && (s[i+n] == '1'):
+					p.Stress, n = PrimaryStress, n+1
+				case (stress == RomanStressDigits) && (i+n < len(s)) && (s[i+n] == '2'):
+					p.Stress, n = SecondaryStress, n+1
+				case (stress == RomanStressUpper) && (s[i:i+n] != strings.ToLower(s[i:i+n])):
+					p.Stress = PrimaryStress
+				}
+			}
+			phonemes = append(phonemes, p)
+			i, afterLetter = i+n, true
+			continue
+		}
+
+		if (s[i] == romanSeparator) && afterLetter {
+			if _, n := a.readRoman(s[i+1:]); n > 0 {
+				i, afterLetter = i+1, false
+				continue
+			}
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if !strings.ContainsRune(alphabetPunctuation, r) {
+			return nil, fmt.Errorf("miileeniol: can't read %q, in %q, as romanized %s", r, s, a.Name)
+		}
+		phonemes = append(phonemes, Phoneme{Symbol: string(r)})
+		i, afterLetter = i+n, false
+	}
+	return phonemes, nil
+}
+
+// readRoman returns the key, in a.Roman, of the longest romanization that s
+// starts with (ignoring case), and that romanization's length.
+func (a *Alphabet) readRoman(s string) (key string, n int) {
+	for k, v := range a.Roman {
+		if (len(v) > n) && (len(v) <= len(s)) && strings.EqualFold(s[:len(v)], v) {
+			key, n = k, len(v)
+		}
+	}
+	return key, n
+}
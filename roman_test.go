@@ -0,0 +1,95 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"testing"
+)
+
+// romanKeys returns phonemes as their keys in a.Roman, with the stress that
+// a romanization with the given stress marks.
+func romanKeys(a *Alphabet, phonemes []Phoneme, stress RomanStress) []string {
+	keys := make([]string, len(phonemes))
+	for i, p := range phonemes {
+		k := alphabetKey(p, func(k string) bool {
+			_, ok := a.Roman[k]
+			return ok
+		})
+		if p.IsVowel() {
+			switch {
+			case (stress == RomanStressDigits) && (p.Stress == PrimaryStress):
+				k += "1"
+			case (stress == RomanStressDigits) && (p.Stress == SecondaryStress):
+				k += "2"
+			case (stress == RomanStressUpper) && (p.Stress == PrimaryStress):
+				k += "1"
+			}
+		}
+		keys[i] = k
+	}
+	return keys
+}
+
+func TestRomanizeRoundTrip(t *testing.T) {
+	d, err := NewEmbeddedDictionary()
+	if err != nil {
+		t.Fatalf("NewEmbeddedDictionary: %v", err)
+	}
+	for _, name := range BundledAlphabetNames() {
+		a, err := BundledAlphabet(name)
+		if err != nil {
+			t.Fatalf("BundledAlphabet(%q): %v", name, err)
+		}
+		for _, stress := range [...]RomanStress{RomanStressNone, RomanStressDigits, RomanStressUpper} {
+			numTested, numFailures := 0, 0
+			for _, k := range d.Keys() {
+				v, _ := d.Lookup(k)
+				phonemes, err := ParsePronunciation(v)
+				if err != nil {
+					t.Fatalf("%s: ParsePronunciation(%q): %v", k, v, err)
+				}
+				drawable := true
+				for _, p := range phonemes {
+					if _, ok := a.roman(p); !ok {
+						drawable = false
+						break
+					}
+				}
+				if !drawable {
+					continue
+				}
+				numTested++
+
+				roman := a.Romanize(phonemes, stress)
+				got, err := a.ParseRoman(roman, stress)
+				if err != nil {
+					t.Errorf("%s, %s, stress %d: ParseRoman(%q): %v", name, k, stress, roman, err)
+				} else if g, w := romanKeys(a, got, stress), romanKeys(a, phonemes, stress); !equalStrings(g, w) {
+					t.Errorf("%s, %s, stress %d: %q parsed as %q, want %q", name, k, stress, roman, g, w)
+				} else {
+					continue
+				}
+				if numFailures++; numFailures == 10 {
+					t.Fatalf("%s, stress %d: too many failures", name, stress)
+				}
+			}
+			if numTested == 0 {
+				t.Errorf("%s, stress %d: no words tested", name, stress)
+			}
+		}
+	}
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+// supplement holds pronunciations that are missing from (or, for words like
+// "A" or "THE", ambiguous in) Britfone. The "%E", "%I", etc. suffixes
+// disambiguate heteronyms and match markers in the input text.
+//
+// Inflected forms and contractions, such as "DIMMED" or "HE'LL", are not
+// listed if the Transliterator can derive them from their base words.
+var supplement = map[string]string{
+	"A":             "ˈə",
+	"AESTHETIC":     "ɛ s θ ˈɛ t ɪ k",
+	"AFTERTHOUGHT":  "ˈɑː f t ə θ ɔː t",
+	"AGUE":          "ˈeɪ g j uː",
+	"ALARUMS":       "ə l ˈɑː ɹ ɐ m z",
+	"AMBLING":       "ˈæ m b l ɪ ŋ",
+	"AMMUNITION":    "æ m j ʊ n ˈɪ ʃ ə n",
+	"AMOROUS":       "ˈæ m ə ɹ ə s",
+	"AN":            "ˈæ n",
+	"ANARCHY":       "ˈæ n ɑː k i",
+	"AND":           "ˈæ n d",
+	"ARE":           "ˈɑː",
+	"AS":            "ˈæ z",
+	"ASSORTED":      "ə s ˈɔː t ɪ d",
+	"ASSORTMENT":    "ə s ˈɔː t m ə n t",
+	"AT":            "ˈæ t",
+	"AUGHT":         "ˈɔː t",
+	"BALDRIC":       "b ˈɔː l d ɹ ɪ k",
+	"BE":            "b ˈiː",
+	"BECAUSE":       "b ɪ k ˈɒ z",
+	"BENEVOLENCE":   "b ɛ n ˈɛ v ə l ə n s",
+	"BILLIONTH":     "b ˈɪ l j ə n θ",
+	"BLADED":        "b l ˈeɪ d ə d",
+	"BLOWPIPE":      "b l ˈəʊ p aɪ p",
+	"BRAILLE":       "b ɹ ˈeɪ l",
+	"CALTROPS":      "k ˈæ l t ɹ ə p s",
+	"CAN":           "k ˈæ n",
+	"CELSIUS":       "s ˈɛ l s i ə s",
+	"CENTIMETER":    "s ˈɛ n t ɪ m iː t ə",
+	"CENTIMETRE":    "s ˈɛ n t ɪ m ˌiː t ə",
+	"CHAINMAIL":     "tʃ ˈeɪ n m eɪ l",
+	"CHEESEWIRE":    "tʃ ˈiː z w aɪ ə",
+	"CONSECRATE":    "k ˈɒ n s ɪ k ɹ ˌeɪ t",
+	"CONSOLE":       "k ə n s ˈəʊ l",
+	"CORKED":        "k ˈɔː k d",
+	"CORPS":         "k ˈɔː",
+	"CROSSBOW":      "k ɹ ˈɒ s b əʊ",
+	"CUNNING":       "k ˈɐ n ɪ ŋ",
+	"CURTAIL'D":     "k ɜː t ˈeɪ l d",
+	"DAGGER":        "d ˈæ g ə",
+	"DARKENED":      "d ˈɑː k ə n d",
+	"DEFORMED":      "d ɪ f ˈɔː m d",
+	"DETRACT":       "d ɪ t ɹ ˈæ k t",
+	"DEVOTION":      "d ɪ v ˈəʊ ʃ ə n",
+	"DID":           "d ˈɪ d",
+	"DIMENSION":     "d aɪ m ˈɛ n ʃ ə n",
+	"DIMENSIONS":    "d aɪ m ˈɛ n ʃ ə n z",
+	"DISAPPEARING":  "d ɪ s ə p ˈɪə ɹ ɪ ŋ",
+	"DISCONTENT":    "d ɪ s k ə n t ˈɛ n t",
+	"DISSEMBLING":   "d ɪ s ˈɛ m b l ɪ ŋ",
+	"DOES":          "d ˈɐ z",
+	"DREARY":        "d ɹ ˈɪə ɹ i",
+	"EARTHMOVING":   "ˈɜː θ m uː v ɪ ŋ",
+	"EIGHTIETH":     "ˈeɪ t i ɪ θ",
+	"EMBER":         "ˈɛ m b ə",
+	"ENGAGED":       "ɪ n g ˈeɪ dʒ d",
+	"ENTREATING":    "ɛ n t ɹ ˈiː t ɪ ŋ",
+	"EURASIAN":      "j ʊə ɹ ˈeɪ ʒ ə n",
+	"EVERMORE":      "ˈɛ v ə m ɔː",
+	"EVERY":         "ˈɛ v ɹ i",
+	"FAHRENHEIT":    "f ˈæ ɹ ə n h ˌaɪ t",
+	"FALCON":        "f ˈæ l k ə n",
+	"FIFTIETH":      "f ˈɪ f t i ɪ θ",
+	"FOR":           "f ˈɔː",
+	"FORTIETH":      "f ˈɔː t i ɪ θ",
+	"GOVERNMENT":    "g ˈɐ v ə n m ə n t",
+	"GRAPNEL":       "g ɹ ˈæ p n ə l",
+	"GYRE":          "dʒ ˈaɪ ə",
+	"HAD":           "h ˈæ d",
+	"HAPPENETH":     "h ˈæ p ə n ɛ θ",
+	"HAS":           "h ˈæ z",
+	"HATH":          "h ˈæ θ",
+	"HAVE":          "h ˈæ v",
+	"HEADGEAR":      "h ˈɛ d g ɪə",
+	"HIS":           "h ˈɪ z",
+	"HUNDREDTH":     "h ˈɐ n d ɹ ə d θ",
+	"IF":            "ˈɪ f",
+	"IN":            "ˈɪ n",
+	"INSIDE":        "ɪ n s ˈaɪ d",
+	"INTEREST":      "ˈɪ n t ɹ ɪ s t",
+	"INTERESTING":   "ˈɪ n t ɹ ɪ s t ɪ ŋ",
+	"INTO":          "ɪ n t ˈuː",
+	"INTRICATE":     "ˈɪ n t ɹ ɪ k ə t",
+	"IS":            "ˈɪ z",
+	"IT":            "ˈɪ t",
+	"ITS":           "ˈɪ t s",
+	"JAUNTY":        "dʒ ˈɔː n t i",
+	"JUST":          "dʒ ˈɐ s t",
+	"KILOGRAM":      "k ˈɪ l ə g ɹ ˌæ m",
+	"KLATCHIAN":     "k l ˈæ tʃ ɪə n",
+	"KNUCKLES":      "n ˈɐ k ə l z",
+	"LASCIVIOUS":    "l ə s ˈɪ v i ə s",
+	"LEAD%E":        "l ˈɛ d",
+	"LENORE":        "l ɛ n ˈɔː",
+	"LIVE%I":        "l ˈɪ v",
+	"LIVES%A":       "l ˈaɪ v z",
+	"LOCKPICKS":     "l ˈɒ k p ɪ k s",
+	"LORE":          "l ˈɔː",
+	"LOUR'D":        "l ˈɔː d",
+	"LUTE":          "l ˈuː t",
+	"MILLIGRAM":     "m ˈɪ l ɪ g ɹ ˌæ m",
+	"MILLILITRE":    "m ˈɪ l ɪ l ˌiː t ə",
+	"MILLIMETRE":    "m ˈɪ l ɪ m ˌiː t ə",
+	"MILLIONTH":     "m ˈɪ l j ə n θ",
+	"MORROW":        "m ˈɒ ɹ əʊ",
+	"NAMELESS":      "n ˈeɪ m l ɪ s",
+	"NEITHER":       "n ˈaɪ ð ə",
+	"NINETIETH":     "n ˈaɪ n t i ɪ θ",
+	"NYMPH":         "n ˈɪ m f",
+	"OBLITERATION":  "ə b l ɪ t ə ɹ ˈeɪ ʃ ə n",
+	"OF":            "ˈɒ v",
+	"OFTEN":         "ˈɒ f ə n",
+	"OR":            "ˈɔː",
+	"PERMIT":        "p ə m ˈɪ t",
+	"POLLINATE":     "p ˈɒ l ə n eɪ t",
+	"POOR":          "p ˈɔː",
+	"PRAYER":        "p ɹ ˈɛə",
+	"QUAINT":        "k w ˈeɪ n t",
+	"RADIANT":       "ɹ ˈeɪ d ɪə n t",
+	"RAPIER":        "ɹ ˈeɪ p ɪə",
+	"READ%I":        "ɹ ˈiː d",
+	"RESEARCHER":    "ɹ ˈiː s ɜː t ə",
+	"RUSTLING":      "ɹ ˈɐ s l ɪ ŋ",
+	"SEER":          "s ˈɪə",
+	"SEPARATE":      "s ˈɛ p ɹ ɪ t",
+	"SEVENTIETH":    "s ˈɛ v ə n t i ɪ θ",
+	"SHALL":         "ʃ ˈæ l",
+	"SHEATHS":       "ʃ ˈiː θ s",
+	"SILHOUETTED":   "s ɪ l ʊ w ˈɛ t ɪ d",
+	"SILKEN":        "s ˈɪ l k ə n",
+	"SIXTIETH":      "s ˈɪ k s t i ɪ θ",
+	"SLINGSHOT":     "s l ˈɪ ŋ ʃ ɒ t",
+	"SOMETIMES":     "s ˈɐ m t ˌaɪ m z",
+	"SPORTIVE":      "s p ˈɔː t ɪ v",
+	"STEEDS":        "s t ˈiː d z",
+	"STUDDING-SAIL": "s t ˈɐ n s ə l",
+	"SURCEASE":      "s ˈɜː s iː s",
+	"SUSY":          "s ˈuː z i",
+	"SWARD":         "s w ˈɔː d",
+	"TEAR%E":        "t ˈɛə",
+	"TEAR%I":        "t ˈɪə",
+	"TEPPIC":        "t ˈɛ p ɪ k",
+	"THAN":          "ð ˈæ n",
+	"THAT":          "ð ˈæ t",
+	"THE":           "ð ˈə",
+	"THEM":          "ð ˈɛ m",
+	"THIS":          "ð ˈɪ s",
+	"THOUSANDTH":    "θ ˈaʊ z ə n d θ",
+	"TIS":           "t ˈɪ z",
+	"TLINGAS":       "t l ˈɪ ŋ g ə z",
+	"TO":            "t ˈuː",
+	"TODAY":         "t ə d ˈeɪ",
+	"UNFASHIONABLE": "ɐ n f ˈæ ʃ ə n ə b ə l",
+	"VIA":           "v ˈiː ə",
+	"VICTORIOUS":    "v ɪ k t ˈɔː ɹ ɪə s",
+	"VISAGED":       "v ɪ z ˈɑː dʒ d",
+	"WANTON":        "w ˈɒ n t ə n",
+	"WAS":           "w ˈɒ z",
+	"WILL":          "w ˈɪ l",
+	"WINDY%I":       "w ˈɪ n d i",
+	"WITH":          "w ˈɪ ð",
+	"WITHOUT":       "w ɪ ð ˈaʊ t",
+	"WOUND%A":       "w ˈaʊ n d",
+	"WREATHS":       "ɹ ˈiː θ s",
+	"WRINKLED":      "ɹ ˈɪ ŋ k ə l d",
+	"YEARS":         "j ˈɪə z",
+	"YOU":           "j ˈuː",
+	"YOUR":          "j ˈɔː",
+	"ZEROTH":        "z ˈɪə ɹ əʊ θ",
+}
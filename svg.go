@@ -0,0 +1,185 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// RenderSVG is like Render but writes the page as SVG to w, with the same
+// layout as Render's image. The Miileeniol letters, their diacritics and
+// stress marks are paths, with the letters' outlines taken from the same
+// font, and the English is text, set in the Go font if the viewer has it.
+// The output depends only on the Renderer's fields and the text, so that it
+// can be diffed. Like Render, an incomplete dictionary still produces an
+// image, and the ErrNotInDictionary error is returned after writing it.
+func (r *Renderer) RenderSVG(w io.Writer, text string) error {
+	f, err := sfnt.Parse(gomono.TTF)
+	if err != nil {
+		return err
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n"+
+		"<rect width=\"%d\" height=\"%d\" fill=\"#ffffff\"/>\n",
+		r.Width, r.Height, r.Width, r.Height, r.Width, r.Height)
+
+	c := &svgCanvas{w: b, font: f}
+	renderErr := r.layout(c, text, r.Width, r.Height)
+	if (renderErr != nil) && !errors.Is(renderErr, ErrNotInDictionary) {
+		return renderErr
+	} else if c.err != nil {
+		return c.err
+	}
+
+	b.WriteString("</svg>\n")
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	return renderErr
+}
+
+// svgFontSize is the gomono font size, in pixels, of makeGlyphs' masks:
+// 26.65, rounded to a fixed.Int26_6 as freetype does.
+const svgFontSize = fixed.Int26_6(1706)
+
+// svgCanvas is a canvas that writes SVG elements.
+type svgCanvas struct {
+	w    *bufio.Writer
+	font *sfnt.Font
+	buf  sfnt.Buffer
+
+	// err is the first error loading a glyph's outline.
+	err error
+}
+
+// svgPath is a vectorPath that builds an SVG path's data.
+type svgPath struct {
+	strings.Builder
+}
+
+// op writes a path command and its coordinates.
+func (p *svgPath) op(cmd string, coords ...float64) {
+	if p.Len() > 0 {
+		p.WriteByte(' ')
+	}
+	p.WriteString(cmd)
+	for _, c := range coords {
+		p.WriteByte(' ')
+		p.WriteString(formatNumber(c))
+	}
+}
+
+func (p *svgPath) moveTo(x float64, y float64) { p.op("M", x, y) }
+func (p *svgPath) lineTo(x float64, y float64) { p.op("L", x, y) }
+func (p *svgPath) closePath()                  { p.op("Z") }
+
+func (p *svgPath) quadTo(x1 float64, y1 float64, x float64, y float64) {
+	p.op("Q", x1, y1, x, y)
+}
+
+func (p *svgPath) cubeTo(x1 float64, y1 float64, x2 float64, y2 float64, x float64, y float64) {
+	p.op("C", x1, y1, x2, y2, x, y)
+}
+
+func (c *svgCanvas) drawGuide(rect image.Rectangle, col color.Color) {
+	fmt.Fprintf(c.w, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"%s/>\n",
+		rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), svgFill(col))
+}
+
+func (c *svgCanvas) drawGlyph(x int, y int, col color.Color, g glyph) {
+	p := &svgPath{}
+	dx := fixed.I(x)
+	for _, r := range g.text {
+		i, err := c.font.GlyphIndex(&c.buf, r)
+		if err != nil {
+			c.setErr(err)
+			return
+		}
+		segments, err := c.font.LoadGlyph(&c.buf, i, svgFontSize, nil)
+		if err != nil {
+			c.setErr(err)
+			return
+		}
+		addOutline(p, segments, dx, fixed.I(y+26))
+		advance, err := c.font.GlyphAdvance(&c.buf, i, svgFontSize, font.HintingFull)
+		if err != nil {
+			c.setErr(err)
+			return
+		}
+		dx += advance
+	}
+	addGlyphDiacritic(p, x, y, g)
+	c.writePath(p, col, false)
+}
+
+func (c *svgCanvas) drawStressMark(x int, y int, col color.Color, m StressMark) {
+	p := &svgPath{}
+	evenOdd := addStressMark(p, x, y, m)
+	c.writePath(p, col, evenOdd)
+}
+
+func (c *svgCanvas) drawOverline(x0 int, x1 int, y int, col color.Color) {
+	p := &svgPath{}
+	addOverline(p, x0, x1, y)
+	c.writePath(p, col, false)
+}
+
+func (c *svgCanvas) drawSyllableBoundary(x int, y int, col color.Color) {
+	p := &svgPath{}
+	addSyllableBoundary(p, x, y)
+	c.writePath(p, col, false)
+}
+
+func (c *svgCanvas) drawEnglish(x int, y int, col color.Color, line string) {
+	if line == "" {
+		return
+	}
+	fmt.Fprintf(c.w, "<text x=\"%d\" y=\"%d\" font-family=\"Go, sans-serif\" font-size=\"26.5\""+
+		" xml:space=\"preserve\"%s>", x, y+26, svgFill(col))
+	xml.EscapeText(c.w, []byte(line))
+	c.w.WriteString("</text>\n")
+}
+
+func (c *svgCanvas) writePath(p *svgPath, col color.Color, evenOdd bool) {
+	if p.Len() == 0 {
+		return
+	}
+	fillRule := ""
+	if evenOdd {
+		fillRule = " fill-rule=\"evenodd\""
+	}
+	fmt.Fprintf(c.w, "<path d=\"%s\"%s%s/>\n", p.String(), svgFill(col), fillRule)
+}
+
+func (c *svgCanvas) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// svgFill returns the fill attributes for col.
+func svgFill(col color.Color) string {
+	n := color.NRGBAModel.Convert(col).(color.NRGBA)
+	s := fmt.Sprintf(" fill=\"#%02x%02x%02x\"", n.R, n.G, n.B)
+	if n.A != 0xFF {
+		s += fmt.Sprintf(" fill-opacity=\"%s\"", strconv.FormatFloat(float64(n.A)/0xFF, 'f', 3, 64))
+	}
+	return s
+}
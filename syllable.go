@@ -0,0 +1,79 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+)
+
+// Syllable is a syllable of a pronunciation: the phonemes [Start, End) of
+// it (or, in a Word, of its Letters).
+type Syllable struct {
+	Start, End int
+
+	// Stress is the stress of the syllable's vowel.
+	Stress Stress
+}
+
+// legalOnsets are the English consonant clusters, of two or three
+// consonants, that can start a syllable. Any single consonant other than /ŋ/
+// can start one.
+var legalOnsets = wordSet("" +
+	"p|l p|ɹ p|j b|l b|ɹ b|j t|ɹ t|w t|j d|ɹ d|w d|j k|l k|ɹ k|w k|j " +
+	"g|l g|ɹ g|w g|j f|l f|ɹ f|j v|j θ|ɹ θ|w θ|j ʃ|ɹ s|p s|t s|k s|m s|n " +
+	"s|f s|l s|w s|j m|j n|j h|j l|j " +
+	"s|p|l s|p|ɹ s|p|j s|t|ɹ s|t|j s|k|ɹ s|k|w s|k|l s|k|j s|m|j")
+
+// isLegalOnset returns whether the consonants can start a syllable.
+func isLegalOnset(consonants []Phoneme) bool {
+	switch len(consonants) {
+	case 0:
+		return true
+	case 1:
+		return consonants[0].Symbol != "ŋ"
+	}
+	symbols := make([]string, len(consonants))
+	for i, c := range consonants {
+		symbols[i] = c.Symbol
+	}
+	return legalOnsets[strings.Join(symbols, "|")]
+}
+
+// Syllabify splits a word's phonemes into syllables, one per vowel. The
+// consonants between two vowels are split by the maximal onset principle:
+// the next syllable starts with as many of them as form a legal English
+// onset, such as /stɹ/ in "astray", and the rest end the previous syllable.
+// Consonants before the first vowel or after the last one belong to the
+// first or last syllable. A word with no vowels is one syllable.
+func Syllabify(phonemes []Phoneme) []Syllable {
+	nuclei := []int(nil)
+	for i, p := range phonemes {
+		if p.IsVowel() {
+			nuclei = append(nuclei, i)
+		}
+	}
+	if len(nuclei) == 0 {
+		if len(phonemes) == 0 {
+			return nil
+		}
+		return []Syllable{{Start: 0, End: len(phonemes)}}
+	}
+
+	syllables := make([]Syllable, len(nuclei))
+	start := 0
+	for k, n := range nuclei {
+		end := len(phonemes)
+		if k+1 < len(nuclei) {
+			next := nuclei[k+1]
+			end = n + 1
+			for !isLegalOnset(phonemes[end:next]) {
+				end++
+			}
+		}
+		syllables[k] = Syllable{Start: start, End: end, Stress: phonemes[n].Stress}
+		start = end
+	}
+	return syllables
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyllabify(t *testing.T) {
+	testCases := []struct {
+		pronunciation string
+		want          string
+	}{
+		{"", ""},
+		{"h m", "h m"},
+		{"ˈɑː", "ˈɑː"},
+		{"k ˈæ t", "k ˈæ t"},
+		{"ə s t ɹ ˈeɪ", "ə | s t ɹ ˈeɪ"},
+		{"ˈɛ k s t ɹ ə", "ˈɛ k | s t ɹ ə"},
+		{"ˈæ t l ə s", "ˈæ t | l ə s"},
+		{"n ˈeɪ tʃ ə", "n ˈeɪ | tʃ ə"},
+		{"ˈɪ ŋ g l ɪ ʃ", "ˈɪ ŋ | g l ɪ ʃ"},
+		{"s ˈɪ ŋ ɪ ŋ", "s ˈɪ ŋ | ɪ ŋ"},
+		{"ɪ n s p ˈaɪ ə", "ɪ n | s p ˈaɪ | ə"},
+		{"ˌɐ n d ə s t ˈæ n d", "ˌɐ n | d ə | s t ˈæ n d"},
+	}
+	for _, tc := range testCases {
+		phonemes, err := ParsePronunciation(tc.pronunciation)
+		if err != nil {
+			t.Fatalf("ParsePronunciation(%q): %v", tc.pronunciation, err)
+		}
+		syllables := Syllabify(phonemes)
+
+		parts := []string(nil)
+		for i, s := range syllables {
+			if (i > 0) && (s.Start != syllables[i-1].End) {
+				t.Errorf("%q: syllable %d starts at %d, not %d", tc.pronunciation, i, s.Start, syllables[i-1].End)
+			}
+			stress := Unstressed
+			for _, p := range phonemes[s.Start:s.End] {
+				if p.IsVowel() {
+					stress = p.Stress
+				}
+			}
+			if s.Stress != stress {
+				t.Errorf("%q: syllable %d has stress %d, want %d", tc.pronunciation, i, s.Stress, stress)
+			}
+			parts = append(parts, FormatPronunciation(phonemes[s.Start:s.End]))
+		}
+		if got := strings.Join(parts, " | "); got != tc.want {
+			t.Errorf("Syllabify(%q): got %q, want %q", tc.pronunciation, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// TextForm is the form of the plain text written by a Renderer's RenderText.
+type TextForm int
+
+const (
+	// TextNFC is Unicode text, in Normalization Form C. Letters' diacritics
+	// are combining characters: U+0307 COMBINING DOT ABOVE or U+0305
+	// COMBINING OVERLINE, on every character of the letter so that the
+	// overline spans a diphthong. Stress marks are U+0323 COMBINING DOT
+	// BELOW, U+0325 COMBINING RING BELOW or U+0331 COMBINING MACRON BELOW.
+	// Normalization composes, for example, "e" and a dot above as "ė".
+	TextNFC TextForm = iota
+
+	// TextNFD is like TextNFC but in Normalization Form D, so that every
+	// diacritic is a separate combining character.
+	TextNFD
+
+	// TextASCII is ASCII text, for where combining characters aren't
+	// supported. Letters are written as in an Alphabet's Letters map, such
+	// as "Mı~Le~Nıε~L", with non-ASCII characters replaced as per
+	// asciiFallbacks. Stress marks are a '^', '`' or '_' (for a dot, ring or
+	// bar) before the letter. None of them is punctuation, and an apostrophe
+	// is written as "\\'" so that it can't be read as a dot above.
+	TextASCII
+)
+
+// Combining characters.
+const (
+	combiningOverline    = '̅'
+	combiningDotAbove    = '̇'
+	combiningDotBelow    = '̣'
+	combiningRingBelow   = '̥'
+	combiningMacronBelow = '̱'
+)
+
+// syllableBoundaryText is written between syllables, like the raised dot
+// drawn by drawSyllableBoundary, in TextNFC and TextNFD form.
+const syllableBoundaryText = "·"
+
+// asciiSyllableBoundaryText is written between syllables in TextASCII form.
+const asciiSyllableBoundaryText = "|"
+
+// asciiFallbacks are the replacements, in TextASCII form, for the default
+// Alphabet's non-ASCII characters and for non-ASCII punctuation. Other
+// non-ASCII characters lose their diacritics, if that leaves an ASCII
+// character, or are replaced by a '?'.
+var asciiFallbacks = map[rune]string{
+	'ı': "i",
+	'ε': "3",
+	'Γ': "Ng",
+	'Δ': "Dh",
+	'Θ': "Th",
+	'Ж': "Zh",
+	'Ч': "Ch",
+	'‘': "'",
+	'’': "'",
+	'‚': ",",
+	'“': "\"",
+	'”': "\"",
+	'„': "\"",
+	'«': "<<",
+	'»': ">>",
+	'‹': "<",
+	'›': ">",
+	'–': "-",
+	'―': "--",
+	'—': "--",
+	'¡': "!",
+	'¿': "?",
+	'…': "...",
+	'•': "*",
+	'·': ".",
+}
+
+// RenderText is like Render but writes text, in the given form, to w, one
+// line per line of text. Only the Miileeniol is written, not the English, and
+// the lines aren't wrapped. Like Render, an incomplete dictionary still
+// produces text, and the ErrNotInDictionary error is returned after writing
+// it.
+func (r *Renderer) RenderText(w io.Writer, text string, form TextForm) error {
+	lines, transliterateErr := r.Transliterator.TransliterateText(text)
+	if lines == nil {
+		return transliterateErr
+	}
+
+	sb := strings.Builder{}
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for _, word := range line {
+			if word.Letters == nil {
+				continue
+			}
+			sb.WriteString(word.Space)
+			if err := r.writeTextWord(&sb, word, form); err != nil {
+				return err
+			}
+		}
+	}
+
+	s := sb.String()
+	switch form {
+	case TextNFC:
+		s = norm.NFC.String(s)
+	case TextNFD:
+		s = norm.NFD.String(s)
+	}
+	b := bufio.NewWriter(w)
+	b.WriteString(s)
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	return transliterateErr
+}
+
+func (r *Renderer) writeTextWord(sb *strings.Builder, w Word, form TextForm) error {
+	syllable := 0
+	for i, l := range w.Letters {
+		g, ok := r.glyphs[alphabetKey(l, func(k string) bool {
+			_, ok := r.glyphs[k]
+			return ok
+		})]
+		if !ok {
+			return fmt.Errorf("miileeniol: couldn't draw %q", w.English)
+		}
+
+		if r.SyllableBoundaries {
+			for ; (syllable < len(w.Syllables)) && (w.Syllables[syllable].End <= i); syllable++ {
+			}
+			if (syllable > 0) && (syllable < len(w.Syllables)) && (w.Syllables[syllable].Start == i) &&
+				(w.Syllables[syllable-1].End == i) {
+				if form == TextASCII {
+					sb.WriteString(asciiSyllableBoundaryText)
+				} else {
+					sb.WriteString(syllableBoundaryText)
+				}
+			}
+		}
+
+		mark := StressMarkNone
+		switch l.Stress {
+		case PrimaryStress:
+			mark = r.PrimaryStressMark
+		case SecondaryStress:
+			mark = r.SecondaryStressMark
+		}
+
+		if form == TextASCII {
+			writeASCIILetter(sb, g, mark)
+		} else {
+			writeUnicodeLetter(sb, g, mark)
+		}
+	}
+	return nil
+}
+
+func writeUnicodeLetter(sb *strings.Builder, g glyph, mark StressMark) {
+	for i, c := range []rune(g.text) {
+		sb.WriteRune(c)
+		if i == 0 {
+			switch mark {
+			case StressMarkDot:
+				sb.WriteRune(combiningDotBelow)
+			case StressMarkRing:
+				sb.WriteRune(combiningRingBelow)
+			case StressMarkBar:
+				sb.WriteRune(combiningMacronBelow)
+			}
+			if g.diacritic == '\'' {
+				sb.WriteRune(combiningDotAbove)
+			}
+		}
+		if g.diacritic == '~' {
+			sb.WriteRune(combiningOverline)
+		}
+	}
+}
+
+func writeASCIILetter(sb *strings.Builder, g glyph, mark StressMark) {
+	switch mark {
+	case StressMarkDot:
+		sb.WriteByte('^')
+	case StressMarkRing:
+		sb.WriteByte('`')
+	case StressMarkBar:
+		sb.WriteByte('_')
+	}
+	for _, c := range g.text {
+		if (c == '\'') || ((g.diacritic == 0) && (asciiFallbacks[c] == "'")) {
+			sb.WriteString("\\'")
+		} else if c < 0x80 {
+			sb.WriteRune(c)
+		} else if s, ok := asciiFallbacks[c]; ok {
+			sb.WriteString(s)
+		} else if d := []rune(norm.NFD.String(string(c))); d[0] < 0x80 {
+			sb.WriteRune(d[0])
+		} else {
+			sb.WriteByte('?')
+		}
+	}
+	if g.diacritic != 0 {
+		sb.WriteByte(g.diacritic)
+	}
+}
@@ -0,0 +1,657 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrNotInDictionary is returned (wrapped) for words that have no
+// pronunciation.
+var ErrNotInDictionary = errors.New("miileeniol: word not in dictionary")
+
+// Word is a transliterated word.
+type Word struct {
+	// English is the upper-cased English word, including any trailing
+	// punctuation and heteronym marker such as "%E".
+	English string
+
+	// Space is the whitespace that preceded the word in its line. It is only
+	// set by TransliterateText.
+	Space string
+
+	// Pronunciation is the dictionary pronunciation, in Britfone's format.
+	// It is empty for punctuation.
+	Pronunciation string
+
+	// Suffix is the trailing punctuation, after the pronounced part.
+	Suffix string
+
+	// Guessed is whether the word was missing from the dictionary, so that
+	// its Pronunciation was guessed by the Transliterator's G2P.
+	Guessed bool
+
+	// Letters are the written letters: the phonemes of Pronunciation (or
+	// of its expansion's words, separated by spaces), then the punctuation
+	// of Suffix. A primary stressed letter is drawn with a dot below.
+	Letters []Phoneme
+
+	// Syllables are the syllables of the phonemes in Letters, as per
+	// Syllabify.
+	Syllables []Syllable
+}
+
+// VariantPolicy is how a Transliterator picks between a word's numbered
+// variants, such as "RALEIGH(1)" and "RALEIGH(2)", when the text doesn't pick
+// one with a marker such as "Raleigh%2".
+type VariantPolicy int
+
+const (
+	// VariantFirstStressed picks the first variant that has a primary
+	// stress, or the first variant if none do. Britfone often lists a weak
+	// (unstressed) form first, such as "THE(1), ð ə".
+	VariantFirstStressed VariantPolicy = iota
+
+	// VariantFirst picks the first variant.
+	VariantFirst
+
+	// VariantNone picks nothing: ambiguous words are treated as missing from
+	// the dictionary unless marked.
+	VariantNone
+)
+
+// SymbolPolicy is how a Transliterator handles symbols, such as "&" or "%",
+// that stand alone (are not trailing punctuation of a word).
+type SymbolPolicy int
+
+const (
+	// SymbolWordsIfUndrawable spells out symbols that the Alphabet has no
+	// letter for, such as "&" as "ampersand", and keeps the others, such as
+	// "(", as punctuation.
+	SymbolWordsIfUndrawable SymbolPolicy = iota
+
+	// SymbolGlyphs keeps every symbol as punctuation. Symbols that the
+	// Alphabet has no letter for are an error.
+	SymbolGlyphs
+
+	// SymbolWords spells out every symbol that has an expansion, including
+	// punctuation such as "(" as "open parentheses".
+	SymbolWords
+)
+
+// Transliterator converts English words to Miileeniol letters. Several
+// Transliterators, with different Dictionaries or Alphabets, can co-exist.
+type Transliterator struct {
+	Dictionary *Dictionary
+	Alphabet   *Alphabet
+
+	// VariantPolicy picks between an unmarked word's numbered variants. A
+	// warning is logged whenever such an ambiguous word is seen.
+	VariantPolicy VariantPolicy
+
+	// SymbolPolicy is whether to spell out stand-alone symbols.
+	SymbolPolicy SymbolPolicy
+
+	// Initialisms is whether to read upper-case words that are missing from
+	// the dictionary, such as "BBC", as the names of their letters. Such a
+	// word can still be read as a word by marking it, as in "NATO%W".
+	Initialisms bool
+
+	// Derive is whether to derive the pronunciation of inflected forms and
+	// contractions, such as "DIMMED" or "HE'LL", that are missing from the
+	// dictionary from their base words, such as "DIM", or "HE" and "'LL".
+	Derive bool
+
+	// Heteronyms is whether TransliterateText (and a Renderer's Render) pick
+	// the pronunciation of unmarked heteronyms, such as "lead", from their
+	// context, as per Disambiguate.
+	Heteronyms bool
+
+	// AccentProfile, if non-nil, rewrites every pronunciation, after it is
+	// looked up (or derived or guessed) and before it is spelled.
+	AccentProfile *AccentProfile
+
+	// RomanStress is how Romanize marks stress.
+	RomanStress RomanStress
+
+	// G2P, if non-nil, guesses the pronunciation of words that are missing
+	// from the dictionary.
+	G2P *G2P
+
+	// Logf, if non-nil, is called with warnings, such as a word having no
+	// stressed letter.
+	Logf func(format string, args ...interface{})
+}
+
+// NewTransliterator returns a Transliterator for the given Dictionary and
+// Alphabet, with Initialisms, Derive and Heteronyms set.
+func NewTransliterator(d *Dictionary, a *Alphabet) *Transliterator {
+	return &Transliterator{
+		Dictionary:  d,
+		Alphabet:    a,
+		Initialisms: true,
+		Derive:      true,
+		Heteronyms:  true,
+	}
+}
+
+func (t *Transliterator) logf(format string, args ...interface{}) {
+	if t.Logf != nil {
+		t.Logf(format, args...)
+	}
+}
+
+// TransliterateWord transliterates a single word, as returned by Parse.
+func (t *Transliterator) TransliterateWord(englishWord string) (Word, error) {
+	w := Word{English: englishWord}
+	if englishWord == "" {
+		return w, nil
+	} else if r, _ := utf8.DecodeRuneInString(englishWord); !unicode.IsLetter(r) {
+		if _, ok := t.Alphabet.Letters[string(r)]; !ok {
+			return w, fmt.Errorf("%w: couldn't draw %q", ErrNotInDictionary, englishWord)
+		}
+		w.Letters = append(w.Letters, Phoneme{Symbol: string(r)})
+		return w, nil
+	}
+
+	dictKey, suffix := splitSuffix(englishWord)
+	dictKey = strings.TrimSuffix(dictKey, WordMarker)
+	// The dictionary spells "CAFÉ" as "CAFE".
+	if folded := foldAccents(dictKey); (folded != dictKey) && !t.hasEntry(dictKey) {
+		dictKey = folded
+	}
+
+	variant := 0
+	if strings.HasPrefix(suffix, "%") {
+		i := 1
+		for ; (i < len(suffix)) && ('0' <= suffix[i]) && (suffix[i] <= '9'); i++ {
+		}
+		if i > 1 {
+			variant, _ = strconv.Atoi(suffix[1:i])
+			suffix = suffix[i:]
+		}
+	}
+
+	spelling, err := t.lookup(dictKey, variant)
+	if errors.Is(err, ErrNotInDictionary) && t.Derive && !t.hasEntry(dictKey) {
+		if v, ok := t.derive(dictKey, maxDeriveDepth); ok {
+			spelling, err = v, nil
+		}
+	}
+	if errors.Is(err, ErrNotInDictionary) && (t.G2P != nil) && !t.hasEntry(dictKey) {
+		key := dictKey
+		if i := strings.IndexByte(key, '%'); i >= 0 {
+			key = key[:i]
+		}
+		if guess := t.G2P.Guess(key); guess != "" {
+			t.logf("%s is not in the dictionary, guessing %q", key, guess)
+			spelling, err, w.Guessed = guess, nil, true
+		}
+	}
+	if err != nil {
+		return w, err
+	}
+	return t.spell(w, spelling, suffix)
+}
+
+// spell sets w's Pronunciation, Suffix and Letters, after applying
+// t.AccentProfile to spelling.
+func (t *Transliterator) spell(w Word, spelling string, suffix string) (Word, error) {
+	spelling = t.AccentProfile.Rewrite(spelling)
+	w.Pronunciation, w.Suffix = spelling, suffix
+	letters, numStressed, undrawable := t.appendLetters(nil, spelling)
+	if undrawable != "" {
+		return w, fmt.Errorf("%w: couldn't draw %q (%q)", ErrNotInDictionary, w.English, spelling)
+	}
+	w.Syllables = Syllabify(letters)
+	w.Letters = t.appendSuffix(letters, w.English, suffix)
+	if numStressed == 0 {
+		t.logf("no underdot: %s", w.English)
+	}
+	return w, nil
+}
+
+// appendSuffix appends the punctuation of a word's suffix to letters.
+// Punctuation that t.Alphabet has no letter for, such as "¤", is left out,
+// with a warning, instead of losing the whole word.
+func (t *Transliterator) appendSuffix(letters []Phoneme, englishWord string, suffix string) []Phoneme {
+	for _, r := range suffix {
+		if _, ok := t.Alphabet.Letters[string(r)]; !ok {
+			t.logf("can't draw %q in %s, leaving it out", r, englishWord)
+			continue
+		}
+		letters = append(letters, Phoneme{Symbol: string(r)})
+	}
+	return letters
+}
+
+// appendLetters appends the phonemes of s, a pronunciation in Britfone's
+// format, to dst. It also returns how many of them have primary stress and,
+// if s can't be parsed or t.Alphabet has no letter for a phoneme, that
+// phoneme.
+func (t *Transliterator) appendLetters(dst []Phoneme, s string) (letters []Phoneme, numStressed int, undrawable string) {
+	for _, field := range strings.Fields(s) {
+		p, err := ParsePhoneme(field)
+		if err != nil {
+			return dst, numStressed, field
+		} else if _, ok := t.Alphabet.letter(p); !ok {
+			return dst, numStressed, field
+		}
+		dst = append(dst, p)
+		if p.Stress == PrimaryStress {
+			numStressed++
+		}
+	}
+	return dst, numStressed, ""
+}
+
+// splitSuffix splits an upper-cased word into its dictionary key and its
+// trailing non-letters, such as punctuation or a "%2" marker. Typographic
+// apostrophes in the dictionary key, as in "HE’LL", become ASCII ones.
+func splitSuffix(englishWord string) (dictKey string, suffix string) {
+	for i := len(englishWord); i > 0; {
+		r, n := utf8.DecodeLastRuneInString(englishWord[:i])
+		if unicode.IsLetter(r) {
+			return strings.Replace(englishWord[:i], "’", "'", -1), englishWord[i:]
+		}
+		i -= n
+	}
+	return "", ""
+}
+
+// foldAccents returns s without its letters' accents, as in "CAFE" for
+// "CAFÉ".
+func foldAccents(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+	b := strings.Builder{}
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TransliterateNext splits the next word off s, as per Parse, and
+// transliterates it. s should not start with whitespace.
+//
+// If that word and the ones after it, on the same line, form a multi-word
+// dictionary entry such as "COSTA_RICA" then the longest such phrase is
+// transliterated as one Word, whose English field keeps the original spacing.
+//
+// A word that isn't in the dictionary but is an abbreviation, such as "Mr.",
+// or a symbol, such as "&" (subject to t.SymbolPolicy), is expanded to the
+// words it stands for. Those words form one Word, separated by ' ' Letters.
+// Such a symbol inside a word, as in "R&B", splits it, unless the whole word
+// is in the dictionary.
+// Likewise, a numeral such as "£5.50" or "3rd" is read out as words, while
+// the Word's English field keeps the digits. An initialism such as "BBC" is
+// read out as the names of its letters, subject to t.Initialisms.
+func (t *Transliterator) TransliterateNext(s string) (w Word, remaining string, err error) {
+	if keys, n := readNumeral(s); n > 0 {
+		return t.transliterateNumeral(s, keys, n)
+	}
+	word, remaining := Parse(s)
+	if n := t.Dictionary.PhraseWords(word); n > 1 {
+		if w, r, ok, err := t.transliteratePhrase(s, word, remaining, n); ok || (err != nil) {
+			return w, r, err
+		}
+	}
+	split := false
+	if n := t.symbolSplit(word, s[:len(s)-len(remaining)]); n > 0 {
+		word, remaining, split = strings.ToUpper(s[:n]), s[n:], true
+	}
+	if expansion, suffix := t.expansion(word); expansion != "" {
+		w, err = t.transliterateExpansion(word, expansion, suffix)
+		// A spelled out symbol, such as the "&" in "R&B", is a word of its
+		// own, so separate it from any word that immediately follows.
+		if (err == nil) && (remaining != "") && (remaining[0] > ' ') {
+			w.Letters = append(w.Letters, Phoneme{Symbol: " "})
+		}
+		return w, remaining, err
+	}
+	if t.Initialisms {
+		if w, ok, err := t.transliterateInitialism(word, s[:len(s)-len(remaining)]); ok {
+			return separateSplit(w, split, err), remaining, err
+		}
+	}
+	w, err = t.TransliterateWord(word)
+	return separateSplit(w, split, err), remaining, err
+}
+
+// separateSplit separates w, the part of a word before a symbol that split
+// it, from that spelled out symbol.
+func separateSplit(w Word, split bool, err error) Word {
+	if split && (err == nil) && (len(w.Letters) > 0) && (w.Letters[len(w.Letters)-1].Symbol != " ") {
+		w.Letters = append(w.Letters, Phoneme{Symbol: " "})
+	}
+	return w
+}
+
+// symbolSplit returns the length of token, a word as returned by Parse but
+// not yet upper-cased, before its first symbol that is spelled out, as in
+// "R&B", or 0 if token shouldn't be split.
+func (t *Transliterator) symbolSplit(word string, token string) int {
+	if key, _ := splitSuffix(word); t.hasEntry(key) || (len(t.Dictionary.Expansions(word)) > 0) {
+		return 0
+	}
+	for i, r := range token {
+		if (i == 0) || unicode.IsLetter(r) || (r == '%') {
+			continue
+		} else if _, ok := t.Alphabet.Letters[string(r)]; ok {
+			continue
+		} else if i+utf8.RuneLen(r) == len(token) {
+			break
+		}
+		if len(t.Dictionary.Expansions(string(r))) > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// transliterateNumeral transliterates the numeral s[:n], read as the
+// dictionary keys, and any trailing punctuation that ends its word.
+func (t *Transliterator) transliterateNumeral(s string, keys []string, n int) (w Word, remaining string, err error) {
+	i := n
+	for ; (i < len(s)) && (s[i] > ' ') && endsWord(s, i); i++ {
+	}
+	suffix := ""
+	if (i == len(s)) || (s[i] <= ' ') {
+		suffix, n = s[n:i], i
+	}
+	w, err = t.transliterateExpansion(strings.ToUpper(s[:n]), strings.Join(keys, " "), suffix)
+	// As with spelled out symbols, separate the numeral from any word, as in
+	// "3D", that immediately follows.
+	if (err == nil) && (n < len(s)) && (s[n] > ' ') {
+		w.Letters = append(w.Letters, Phoneme{Symbol: " "})
+	}
+	return w, s[n:], err
+}
+
+// expansion returns what the upper-cased word expands to, if it should be
+// expanded, and the word's trailing punctuation that is not part of the
+// abbreviation.
+func (t *Transliterator) expansion(word string) (expansion string, suffix string) {
+	if r, _ := utf8.DecodeRuneInString(word); !unicode.IsLetter(r) {
+		switch t.SymbolPolicy {
+		case SymbolGlyphs:
+			return "", ""
+		case SymbolWordsIfUndrawable:
+			if _, ok := t.Alphabet.Letters[string(r)]; ok {
+				return "", ""
+			}
+		}
+		if es := t.Dictionary.Expansions(word); len(es) > 0 {
+			return es[0], ""
+		}
+		return "", ""
+	}
+
+	dictKey, suffix := splitSuffix(word)
+	if t.hasEntry(dictKey) {
+		return "", ""
+	}
+	// Try "MR." before "MR", with the full stop being part of the
+	// abbreviation rather than punctuation.
+	if strings.HasPrefix(suffix, ".") {
+		if es := t.Dictionary.Expansions(dictKey + "."); len(es) > 0 {
+			return es[0], suffix[1:]
+		}
+	}
+	if es := t.Dictionary.Expansions(dictKey); len(es) > 0 {
+		return es[0], suffix
+	}
+	return "", ""
+}
+
+// transliterateExpansion transliterates the space-separated dictionary keys
+// of expansion, such as "MILES PER(1) HOUR", as one Word, followed by suffix.
+func (t *Transliterator) transliterateExpansion(englishWord string, expansion string, suffix string) (Word, error) {
+	w := Word{English: englishWord}
+	pronunciations := []string(nil)
+	for i, k := range strings.Fields(expansion) {
+		if base, n := splitVariant(k); n > 0 {
+			k = base + "%" + strconv.Itoa(n)
+		}
+		x, err := t.TransliterateWord(k)
+		if err != nil {
+			return w, fmt.Errorf("expanding %q: %w", englishWord, err)
+		}
+		if i > 0 {
+			w.Letters = append(w.Letters, Phoneme{Symbol: " "})
+		}
+		for _, y := range x.Syllables {
+			y.Start += len(w.Letters)
+			y.End += len(w.Letters)
+			w.Syllables = append(w.Syllables, y)
+		}
+		w.Letters = append(w.Letters, x.Letters...)
+		pronunciations = append(pronunciations, x.Pronunciation)
+	}
+	w.Letters = t.appendSuffix(w.Letters, englishWord, suffix)
+	// Britfone uses an underscore to separate the words of a phrase.
+	w.Pronunciation, w.Suffix = strings.Join(pronunciations, " _ "), suffix
+	return w, nil
+}
+
+// transliteratePhrase looks for the longest dictionary phrase of up to n words
+// starting with word, whose remaining text is rem. Only the last word of a
+// phrase can have trailing punctuation or markers.
+func (t *Transliterator) transliteratePhrase(s string, word string, rem string, n int) (w Word, remaining string, ok bool, err error) {
+	words, ends := []string{word}, []int{len(s) - len(rem)}
+	for (len(words) < n) && (rem != "") {
+		if key, _ := splitSuffix(words[len(words)-1]); key != words[len(words)-1] {
+			break
+		}
+		i := 0
+		for ; (i < len(rem)) && ((rem[i] == ' ') || (rem[i] == '\t')); i++ {
+		}
+		if (i == 0) || (i == len(rem)) {
+			break
+		}
+		word, rem = Parse(rem[i:])
+		if c := word[0]; (c < 'A') || ('Z' < c) {
+			break
+		}
+		words = append(words, word)
+		ends = append(ends, len(s)-len(rem))
+	}
+
+	for k := len(words); k > 1; k-- {
+		phrase := strings.Join(words[:k], "_")
+		if key, _ := splitSuffix(phrase); !t.hasEntry(key) {
+			continue
+		}
+		w, err := t.TransliterateWord(phrase)
+		w.English = strings.ToUpper(s[:ends[k-1]])
+		return w, s[ends[k-1]:], true, err
+	}
+	return Word{}, "", false, nil
+}
+
+// hasEntry returns whether k has a plain or numbered dictionary entry.
+func (t *Transliterator) hasEntry(k string) bool {
+	if _, ok := t.Dictionary.Lookup(k); ok {
+		return true
+	}
+	return len(t.Dictionary.Variants(k)) > 0
+}
+
+// lookup returns the pronunciation of the word k. A positive variant selects
+// one of k's numbered variants. Otherwise, k's plain entry is preferred, and
+// if there isn't one, t.VariantPolicy picks between k's variants.
+func (t *Transliterator) lookup(k string, variant int) (string, error) {
+	if variant > 0 {
+		if v, ok := t.Dictionary.Variant(k, variant); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("%w: %q", ErrNotInDictionary, k+"%"+strconv.Itoa(variant))
+	}
+	if v, ok := t.Dictionary.Lookup(k); ok {
+		return v, nil
+	}
+
+	ns := t.Dictionary.Variants(k)
+	if len(ns) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrNotInDictionary, k)
+	} else if len(ns) == 1 {
+		v, _ := t.Dictionary.Variant(k, ns[0])
+		return v, nil
+	}
+
+	n := t.Dictionary.pickVariant(k, t.VariantPolicy)
+	if n == 0 {
+		t.logf("%s is ambiguous (variants %v); append %%N to choose", k, ns)
+		return "", fmt.Errorf("%w: %q is ambiguous", ErrNotInDictionary, k)
+	}
+	t.logf("%s is ambiguous (variants %v), using %s%%%d; append %%N to choose", k, ns, k, n)
+	v, _ := t.Dictionary.Variant(k, n)
+	return v, nil
+}
+
+// TransliterateText splits text into lines, splits each line into words (as
+// per TransliterateNext) and transliterates each word. Words missing from the dictionary
+// are kept, with no Letters, and the returned error then wraps
+// ErrNotInDictionary and lists every missing word. Any other error is
+// returned immediately.
+func (t *Transliterator) TransliterateText(text string) ([][]Word, error) {
+	if t.Heteronyms {
+		text, _ = t.Disambiguate(text)
+	}
+	lines := [][]Word(nil)
+	missing := []string(nil)
+	for _, line := range strings.Split(text, "\n") {
+		words := []Word{}
+		for s, space := line, 0; s != ""; {
+			if s[space] <= ' ' {
+				if space++; space == len(s) {
+					break
+				}
+				continue
+			}
+			w, remaining, err := t.TransliterateNext(s[space:])
+			if errors.Is(err, ErrNotInDictionary) {
+				missing = append(missing, w.English)
+			} else if err != nil {
+				return nil, err
+			}
+			w.Space = s[:space]
+			words = append(words, w)
+			s, space = remaining, 0
+		}
+		lines = append(lines, words)
+	}
+	if len(missing) > 0 {
+		return lines, fmt.Errorf("%w: %q", ErrNotInDictionary, missing)
+	}
+	return lines, nil
+}
+
+// Romanize returns w's ASCII romanization, as per the Alphabet's Romanize
+// and t.RomanStress. Punctuation is passed through.
+func (t *Transliterator) Romanize(w Word) string {
+	return t.Alphabet.Romanize(w.Letters, t.RomanStress)
+}
+
+// RomanizeText transliterates text, as per TransliterateText, and returns its
+// romanization. The lines, the whitespace between words and punctuation are
+// kept. Words missing from the dictionary are written as their (upper-cased)
+// English and, as for TransliterateText, the returned error then wraps
+// ErrNotInDictionary.
+func (t *Transliterator) RomanizeText(text string) (string, error) {
+	lines, err := t.TransliterateText(text)
+	if lines == nil {
+		return "", err
+	}
+	sb := strings.Builder{}
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for _, w := range line {
+			sb.WriteString(w.Space)
+			if w.Letters == nil {
+				sb.WriteString(w.English)
+			} else {
+				sb.WriteString(t.Romanize(w))
+			}
+		}
+	}
+	return sb.String(), err
+}
+
+// Parse splits the next word off s. Words are upper-cased and run until the
+// next whitespace. A leading non-letter, such as an opening parenthesis, is a
+// word by itself.
+func Parse(s string) (word string, remaining string) {
+	for i := 0; i < len(s); i++ {
+		if r, n := utf8.DecodeRuneInString(s[i:]); r <= ' ' {
+			return strings.ToUpper(s[:i]), s[i:]
+		} else if i != 0 {
+			// No-op.
+		} else if !unicode.IsLetter(r) && (r != '%') {
+			return strings.ToUpper(s[:i+n]), s[i+n:]
+		}
+	}
+	return strings.ToUpper(s), ""
+}
+
+// StripMarkers removes heteronym markers like "%e" and variant markers like
+// "%2" from English text. A '%' that doesn't follow a letter, as in "25%", or
+// that isn't followed by digits or by one ASCII letter ending the word, as in
+// "x% y", is not a marker.
+func StripMarkers(s string) string {
+	for i := 0; ; {
+		k := strings.IndexByte(s[i:], '%')
+		if k < 0 {
+			return s
+		}
+		i += k
+		if r, _ := utf8.DecodeLastRuneInString(s[:i]); !unicode.IsLetter(r) {
+			i++
+			continue
+		}
+		j := i + 1
+		for ; (j < len(s)) && ('0' <= s[j]) && (s[j] <= '9'); j++ {
+		}
+		if (j == i+1) && (j < len(s)) && isAlpha(rune(s[j])) {
+			if r, _ := utf8.DecodeRuneInString(s[j+1:]); !unicode.IsLetter(r) {
+				j++
+			}
+		}
+		if j == i+1 {
+			i++
+			continue
+		}
+		s = s[:i] + s[j:]
+	}
+}
+
+func isAlpha(r rune) bool {
+	switch {
+	case ('A' <= r) && (r <= 'Z'):
+		return true
+	case ('a' <= r) && (r <= 'z'):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStripMarkers(t *testing.T) {
+	testCases := []struct {
+		s, want string
+	}{
+		{"", ""},
+		{"lead%e pipe", "lead pipe"},
+		{"Raleigh%2, NC", "Raleigh, NC"},
+		{"Raleigh%12.", "Raleigh."},
+		{"NATO%W", "NATO"},
+		{"25% off", "25% off"},
+		{"x% y", "x% y"},
+		{"x%", "x%"},
+		{"x%-y", "x%-y"},
+		{"x%ab", "x%ab"},
+		{"x%é", "x%é"},
+		{"x%ée", "x%ée"},
+		{"café%2 au lait", "café au lait"},
+	}
+	for _, tc := range testCases {
+		got := StripMarkers(tc.s)
+		if got != tc.want {
+			t.Errorf("StripMarkers(%q): got %q, want %q", tc.s, got, tc.want)
+		} else if !utf8.ValidString(got) {
+			t.Errorf("StripMarkers(%q): got invalid UTF-8 %q", tc.s, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Nigel Tao.
+//
+// Licensed under the MIT license.
+
+package miileeniol
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// vectorPath is a path being built by a vector canvas, such as svgCanvas, in
+// pixels with the y axis pointing down.
+type vectorPath interface {
+	moveTo(x float64, y float64)
+	lineTo(x float64, y float64)
+	quadTo(x1 float64, y1 float64, x float64, y float64)
+	cubeTo(x1 float64, y1 float64, x2 float64, y2 float64, x float64, y float64)
+	closePath()
+}
+
+// The add functions below add the outlines of what imageCanvas draws pixel
+// by pixel, such as a stress mark, to a vectorPath.
+
+func addRect(p vectorPath, x0 int, y0 int, x1 int, y1 int) {
+	p.moveTo(float64(x0), float64(y0))
+	p.lineTo(float64(x1), float64(y0))
+	p.lineTo(float64(x1), float64(y1))
+	p.lineTo(float64(x0), float64(y1))
+	p.closePath()
+}
+
+// addEllipse adds an ellipse, as four cubic Bézier curves.
+func addEllipse(p vectorPath, cx float64, cy float64, rx float64, ry float64) {
+	// k places the control points so that the curves approximate a quarter
+	// circle.
+	const k = 0.5522847498
+	p.moveTo(cx+rx, cy)
+	p.cubeTo(cx+rx, cy+k*ry, cx+k*rx, cy+ry, cx, cy+ry)
+	p.cubeTo(cx-k*rx, cy+ry, cx-rx, cy+k*ry, cx-rx, cy)
+	p.cubeTo(cx-rx, cy-k*ry, cx-k*rx, cy-ry, cx, cy-ry)
+	p.cubeTo(cx+k*rx, cy-ry, cx+rx, cy-k*ry, cx+rx, cy)
+	p.closePath()
+}
+
+func addOverline(p vectorPath, x0 int, x1 int, y int) {
+	p.moveTo(float64(x0), float64(y))
+	p.lineTo(float64(x1), float64(y))
+	p.lineTo(float64(x1), float64(y+3))
+	p.lineTo(float64(x1-2), float64(y+3))
+	p.lineTo(float64(x1-2), float64(y+2))
+	p.lineTo(float64(x0), float64(y+2))
+	p.closePath()
+}
+
+// addGlyphDiacritic adds the diacritic that makeGlyphs draws on g's mask,
+// for the letter whose top-left corner is at (x, y).
+func addGlyphDiacritic(p vectorPath, x int, y int, g glyph) {
+	if g.diacritic == '\'' {
+		addEllipse(p, float64(x)+8, float64(y)+7.5, 2, 2.5)
+	} else if (g.diacritic == '~') && !g.joinedOverline {
+		addOverline(p, x+3, x+g.mask.Bounds().Dx()-2, y+overlineY(g.vowel))
+	}
+}
+
+// addStressMark adds m, below the letter whose top-left corner is at (x, y).
+// It returns whether the path has to be filled with the even-odd rule.
+func addStressMark(p vectorPath, x int, y int, m StressMark) (evenOdd bool) {
+	switch m {
+	case StressMarkDot:
+		addEllipse(p, float64(x)+8, float64(y)+30.5, 2, 2.5)
+	case StressMarkRing:
+		addEllipse(p, float64(x)+8, float64(y)+31, 3, 3)
+		addEllipse(p, float64(x)+8, float64(y)+31, 2, 2)
+		return true
+	case StressMarkBar:
+		addRect(p, x+4, y+30, x+12, y+32)
+	}
+	return false
+}
+
+func addSyllableBoundary(p vectorPath, x int, y int) {
+	addRect(p, x+2, y+16, x+4, y+18)
+}
+
+// addOutline adds a font glyph's outline, with its origin at (x, y).
+func addOutline(p vectorPath, segments sfnt.Segments, x fixed.Int26_6, y fixed.Int26_6) {
+	f := func(v fixed.Int26_6) float64 {
+		return float64(v) / 64
+	}
+	started := false
+	for _, s := range segments {
+		a := s.Args
+		for i := range a {
+			a[i].X += x
+			a[i].Y += y
+		}
+		switch s.Op {
+		case sfnt.SegmentOpMoveTo:
+			if started {
+				p.closePath()
+			}
+			p.moveTo(f(a[0].X), f(a[0].Y))
+			started = true
+		case sfnt.SegmentOpLineTo:
+			p.lineTo(f(a[0].X), f(a[0].Y))
+		case sfnt.SegmentOpQuadTo:
+			p.quadTo(f(a[0].X), f(a[0].Y), f(a[1].X), f(a[1].Y))
+		case sfnt.SegmentOpCubeTo:
+			p.cubeTo(f(a[0].X), f(a[0].Y), f(a[1].X), f(a[1].Y), f(a[2].X), f(a[2].Y))
+		}
+	}
+	if started {
+		p.closePath()
+	}
+}
+
+// formatNumber formats v for a vector format, to three decimal places but
+// without trailing zeroes, so that output is deterministic.
+func formatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}